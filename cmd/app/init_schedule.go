@@ -4,24 +4,47 @@ import (
 	"github.com/eduardolat/pgbackweb/internal/cron"
 	"github.com/eduardolat/pgbackweb/internal/logger"
 	"github.com/eduardolat/pgbackweb/internal/service"
-	"github.com/google/uuid"
+	"github.com/eduardolat/pgbackweb/internal/service/cluster"
 )
 
 func initSchedule(cr *cron.Cron, servs *service.Service) {
+	// Scheduled runs are claimed in the database, so when multiple instances
+	// share the same database every run happens only once
+	cr.SetLocker(servs.ClusterService)
+
 	/*
-		Initial executions
+		Initial executions, claimed for a startup window so instances that
+		boot in the same deploy don't repeat them
 	*/
 
-	servs.ExecutionsService.SoftDeleteExpiredExecutions()
-	servs.AuthService.DeleteOldSessions()
-	servs.DatabasesService.TestAllDatabases()
-	servs.DestinationsService.TestAllDestinations()
+	// The heartbeat goes first so the reconciliation does not take this
+	// instance as gone
+	servs.ClusterService.Heartbeat()
+	cr.RunOnce("reconcile-interrupted-jobs", func() {
+		servs.ExecutionsService.ReconcileInterruptedExecutions()
+		servs.RestorationsService.ReconcileInterruptedRestorations()
+	})
+	cr.RunOnce("soft-delete-expired-executions", func() {
+		servs.ExecutionsService.SoftDeleteExpiredExecutions()
+	})
+	cr.RunOnce("delete-old-sessions", func() {
+		servs.AuthService.DeleteOldSessions()
+	})
+	cr.RunOnce("test-all-databases", func() {
+		servs.DatabasesService.TestAllDatabases()
+	})
+	cr.RunOnce("test-all-destinations", func() {
+		servs.DestinationsService.TestAllDestinations()
+	})
+	cr.RunOnce("purge-history", func() {
+		servs.HistoryService.PurgeHistory()
+	})
 
 	/*
 		Schedules
 	*/
 
-	err := cr.UpsertJob(uuid.New(), "UTC", "*/10 * * * *", func() {
+	err := cr.UpsertJob(cron.JobID("soft-delete-expired-executions"), "UTC", "*/10 * * * *", func() {
 		servs.ExecutionsService.SoftDeleteExpiredExecutions()
 	})
 	if err != nil {
@@ -31,7 +54,16 @@ func initSchedule(cr *cron.Cron, servs *service.Service) {
 		)
 	}
 
-	err = cr.UpsertJob(uuid.New(), "UTC", "*/10 * * * *", func() {
+	err = cr.UpsertJob(cron.JobID("apply-lifecycle-rules"), "UTC", "*/10 * * * *", func() {
+		servs.ExecutionsService.ApplyLifecycleRules()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling lifecycle rules", logger.KV{"error": err},
+		)
+	}
+
+	err = cr.UpsertJob(cron.JobID("delete-old-sessions"), "UTC", "*/10 * * * *", func() {
 		servs.AuthService.DeleteOldSessions()
 	})
 	if err != nil {
@@ -40,7 +72,7 @@ func initSchedule(cr *cron.Cron, servs *service.Service) {
 		)
 	}
 
-	err = cr.UpsertJob(uuid.New(), "UTC", "*/10 * * * *", func() {
+	err = cr.UpsertJob(cron.JobID("test-all-databases"), "UTC", "*/10 * * * *", func() {
 		servs.DatabasesService.TestAllDatabases()
 	})
 	if err != nil {
@@ -49,7 +81,7 @@ func initSchedule(cr *cron.Cron, servs *service.Service) {
 		)
 	}
 
-	err = cr.UpsertJob(uuid.New(), "UTC", "*/10 * * * *", func() {
+	err = cr.UpsertJob(cron.JobID("test-all-destinations"), "UTC", "*/10 * * * *", func() {
 		servs.DestinationsService.TestAllDestinations()
 	})
 	if err != nil {
@@ -58,5 +90,67 @@ func initSchedule(cr *cron.Cron, servs *service.Service) {
 		)
 	}
 
+	err = cr.UpsertJob(cron.JobID("purge-history"), "UTC", "0 * * * *", func() {
+		servs.HistoryService.PurgeHistory()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling history purge", logger.KV{"error": err},
+		)
+	}
+
+	err = cr.UpsertJob(cron.JobID("delete-old-run-claims"), "UTC", "30 3 * * *", func() {
+		servs.ClusterService.DeleteOldRunClaims()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling deletion of old run claims", logger.KV{"error": err},
+		)
+	}
+
+	err = cr.UpsertJob(cron.JobID("reconcile-interrupted-jobs"), "UTC", "*/5 * * * *", func() {
+		servs.ExecutionsService.ReconcileInterruptedExecutions()
+		servs.RestorationsService.ReconcileInterruptedRestorations()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling reconciliation of interrupted jobs",
+			logger.KV{"error": err},
+		)
+	}
+
+	err = cr.UpsertLocalJob(cron.JobID("node-heartbeat"), "UTC", cluster.HeartbeatCron, func() {
+		servs.ClusterService.Heartbeat()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling node heartbeat", logger.KV{"error": err},
+		)
+	}
+
 	servs.BackupsService.ScheduleAll()
+	servs.BackupsService.CatchUpMissedRuns()
+	servs.ExecutionsService.DispatchQueue()
+
+	// Every instance syncs its backup schedules to pick up the changes made
+	// through other instances
+	err = cr.UpsertLocalJob(cron.JobID("sync-backups-schedules"), "UTC", "* * * * *", func() {
+		servs.BackupsService.ScheduleAll()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling backups schedules sync", logger.KV{"error": err},
+		)
+	}
+
+	// Every instance dispatches the shared queue periodically, so queued
+	// executions start even if the instance that queued them is gone
+	err = cr.UpsertLocalJob(cron.JobID("dispatch-executions-queue"), "UTC", "* * * * *", func() {
+		servs.ExecutionsService.DispatchQueue()
+	})
+	if err != nil {
+		logger.FatalError(
+			"error scheduling executions queue dispatch", logger.KV{"error": err},
+		)
+	}
 }
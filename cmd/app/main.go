@@ -1,6 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/eduardolat/pgbackweb/internal/config"
 	"github.com/eduardolat/pgbackweb/internal/cron"
 	"github.com/eduardolat/pgbackweb/internal/database"
@@ -38,7 +45,7 @@ func main() {
 	dbgen := dbgen.New(db)
 
 	ints := integration.New()
-	servs := service.New(env, dbgen, cr, ints)
+	servs := service.New(env, db, dbgen, cr, ints)
 	initSchedule(cr, servs)
 
 	app := echo.New()
@@ -51,7 +58,20 @@ func main() {
 		"listenHost": env.PBW_LISTEN_HOST,
 		"listenPort": env.PBW_LISTEN_PORT,
 	})
-	if err := app.Start(address); err != nil {
-		logger.FatalError("error starting server", logger.KV{"error": err})
-	}
+	go func() {
+		err := app.Start(address)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.FatalError("error starting server", logger.KV{"error": err})
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM,
+	)
+	<-ctx.Done()
+
+	// A second signal kills the application right away
+	stop()
+
+	shutdown(env, app, servs)
 }
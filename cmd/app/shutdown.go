@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/config"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// shutdown stops the application gracefully. It stops starting new
+// executions and restorations, waits up to PBW_SHUTDOWN_GRACE_PERIOD for the
+// running ones to finish, stops the server and marks the ones still running
+// as interrupted.
+//
+// The scheduler keeps running while draining, so this instance keeps sending
+// heartbeats and other instances don't take its executions as interrupted.
+func shutdown(env config.Env, app *echo.Echo, servs *service.Service) {
+	logger.Info("shutting down, waiting for running jobs to finish", logger.KV{
+		"grace_period": env.PBW_SHUTDOWN_GRACE_PERIOD.String(),
+	})
+
+	graceCtx, cancel := context.WithTimeout(
+		context.Background(), env.PBW_SHUTDOWN_GRACE_PERIOD,
+	)
+	defer cancel()
+
+	drained := true
+	if err := servs.ExecutionsService.DrainExecutions(graceCtx); err != nil {
+		drained = false
+	}
+	if err := servs.RestorationsService.DrainRestorations(graceCtx); err != nil {
+		drained = false
+	}
+
+	serverCtx, cancelServer := context.WithTimeout(
+		context.Background(), 10*time.Second,
+	)
+	defer cancelServer()
+	if err := app.Shutdown(serverCtx); err != nil {
+		logger.Error("error shutting down server", logger.KV{"error": err})
+	}
+
+	if !drained {
+		logger.Info("grace period is over, marking running jobs as interrupted")
+		servs.ExecutionsService.InterruptRunningExecutions()
+		servs.RestorationsService.InterruptRunningRestorations()
+	}
+
+	logger.Info("shutdown completed")
+}
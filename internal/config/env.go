@@ -2,6 +2,7 @@ package config
 
 import (
 	"sync"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -12,6 +13,17 @@ type Env struct {
 	PBW_POSTGRES_CONN_STRING string `env:"PBW_POSTGRES_CONN_STRING,required"`
 	PBW_LISTEN_HOST          string `env:"PBW_LISTEN_HOST" envDefault:"0.0.0.0"`
 	PBW_LISTEN_PORT          string `env:"PBW_LISTEN_PORT" envDefault:"8085"`
+
+	PBW_SHUTDOWN_GRACE_PERIOD   time.Duration `env:"PBW_SHUTDOWN_GRACE_PERIOD" envDefault:"5m"`
+	PBW_NODE_TIMEOUT_HEARTBEATS int           `env:"PBW_NODE_TIMEOUT_HEARTBEATS" envDefault:"3"`
+
+	PBW_DOWNLOAD_MODE            string        `env:"PBW_DOWNLOAD_MODE" envDefault:"presigned"`
+	PBW_PRESIGNED_URL_EXPIRATION time.Duration `env:"PBW_PRESIGNED_URL_EXPIRATION" envDefault:"12h"`
+	PBW_UPLOAD_RETRY_WINDOW      time.Duration `env:"PBW_UPLOAD_RETRY_WINDOW" envDefault:"15m"`
+	PBW_HISTORY_RETENTION_DAYS   int           `env:"PBW_HISTORY_RETENTION_DAYS" envDefault:"0"`
+
+	PBW_MAX_RUNNING_EXECUTIONS            int `env:"PBW_MAX_RUNNING_EXECUTIONS" envDefault:"0"`
+	PBW_MAX_RUNNING_EXECUTIONS_PER_SERVER int `env:"PBW_MAX_RUNNING_EXECUTIONS_PER_SERVER" envDefault:"0"`
 }
 
 var (
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/eduardolat/pgbackweb/internal/validate"
 )
@@ -16,5 +17,37 @@ func validateEnv(env Env) error {
 		return fmt.Errorf("invalid listen port %s, valid values are 1-65535", env.PBW_LISTEN_PORT)
 	}
 
+	if env.PBW_DOWNLOAD_MODE != "proxy" && env.PBW_DOWNLOAD_MODE != "presigned" {
+		return fmt.Errorf("invalid download mode %s, valid values are proxy and presigned", env.PBW_DOWNLOAD_MODE)
+	}
+
+	if env.PBW_PRESIGNED_URL_EXPIRATION <= 0 || env.PBW_PRESIGNED_URL_EXPIRATION > time.Hour*24*7 {
+		return fmt.Errorf("invalid presigned url expiration %s, valid values are 1s-168h", env.PBW_PRESIGNED_URL_EXPIRATION)
+	}
+
+	if env.PBW_UPLOAD_RETRY_WINDOW < 0 {
+		return fmt.Errorf("invalid upload retry window %s, it can't be negative", env.PBW_UPLOAD_RETRY_WINDOW)
+	}
+
+	if env.PBW_SHUTDOWN_GRACE_PERIOD < 0 {
+		return fmt.Errorf("invalid shutdown grace period %s, it can't be negative", env.PBW_SHUTDOWN_GRACE_PERIOD)
+	}
+
+	if env.PBW_NODE_TIMEOUT_HEARTBEATS < 2 {
+		return fmt.Errorf("invalid node timeout heartbeats %d, it must be at least 2", env.PBW_NODE_TIMEOUT_HEARTBEATS)
+	}
+
+	if env.PBW_HISTORY_RETENTION_DAYS < 0 {
+		return fmt.Errorf("invalid history retention days %d, it can't be negative", env.PBW_HISTORY_RETENTION_DAYS)
+	}
+
+	if env.PBW_MAX_RUNNING_EXECUTIONS < 0 {
+		return fmt.Errorf("invalid max running executions %d, it can't be negative", env.PBW_MAX_RUNNING_EXECUTIONS)
+	}
+
+	if env.PBW_MAX_RUNNING_EXECUTIONS_PER_SERVER < 0 {
+		return fmt.Errorf("invalid max running executions per server %d, it can't be negative", env.PBW_MAX_RUNNING_EXECUTIONS_PER_SERVER)
+	}
+
 	return nil
 }
@@ -1,17 +1,41 @@
 package cron
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/eduardolat/pgbackweb/internal/logger"
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
 )
 
+// Locker coordinates the scheduled runs between multiple instances of the
+// application, so every run of a job happens only once.
+type Locker interface {
+	// ClaimRun claims the run of the job with the given key scheduled at the
+	// given time, it returns false if another instance already claimed it.
+	ClaimRun(ctx context.Context, key string, scheduledAt time.Time) (bool, error)
+	// ClaimStartupRun claims the startup run of the job with the given key,
+	// it returns false if another instance claimed it within the window.
+	ClaimStartupRun(ctx context.Context, key string, window time.Duration) (bool, error)
+}
+
+// startupRunWindow is the time during which a startup run claimed by an
+// instance is not repeated by the other instances booting in the same
+// deploy. The startup jobs are also scheduled, so a later boot skipping
+// them is harmless.
+const startupRunWindow = 5 * time.Minute
+
 // Cron is a wrapper around the gocron.Scheduler with the specific
 // configuration for the project.
 type Cron struct {
 	scheduler gocron.Scheduler
+
+	lockerMu sync.RWMutex
+	locker   Locker
 }
 
 // New creates a new instance of the Cron struct.
@@ -28,27 +52,98 @@ func New() (*Cron, error) {
 	}, nil
 }
 
+// SetLocker sets the locker used to coordinate the jobs added with UpsertJob
+// between multiple instances of the application. It must be called before
+// adding the jobs.
+func (c *Cron) SetLocker(locker Locker) {
+	c.lockerMu.Lock()
+	defer c.lockerMu.Unlock()
+	c.locker = locker
+}
+
 // UpsertJob adds a new job to the scheduler and it deletes the job first if
 // it already exists.
+//
+// If a locker is set, every run of the job happens only once across all the
+// instances of the application.
 func (c *Cron) UpsertJob(
 	id uuid.UUID, timeZone string, cronExpression string,
 	function any, parameters ...any,
+) error {
+	return c.upsertJob(id, timeZone, cronExpression, true, function, parameters...)
+}
+
+// UpsertLocalJob is like UpsertJob but the job runs in every instance of the
+// application, it is meant for jobs that manage the state of the instance.
+func (c *Cron) UpsertLocalJob(
+	id uuid.UUID, timeZone string, cronExpression string,
+	function any, parameters ...any,
+) error {
+	return c.upsertJob(id, timeZone, cronExpression, false, function, parameters...)
+}
+
+func (c *Cron) upsertJob(
+	id uuid.UUID, timeZone string, cronExpression string, distributed bool,
+	function any, parameters ...any,
 ) error {
 	if err := c.RemoveJob(id); err != nil {
 		return err
 	}
 
+	options := []gocron.JobOption{
+		gocron.WithIdentifier(id),
+		gocron.WithName(id.String()),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	}
+
+	c.lockerMu.RLock()
+	if distributed && c.locker != nil {
+		options = append(
+			options, gocron.WithDistributedJobLocker(runLocker{locker: c.locker}),
+		)
+	}
+	c.lockerMu.RUnlock()
+
 	exp := fmt.Sprintf("CRON_TZ=%s %s", timeZone, cronExpression)
 	_, err := c.scheduler.NewJob(
 		gocron.CronJob(exp, false),
 		gocron.NewTask(function, parameters...),
-		gocron.WithIdentifier(id),
-		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		options...,
 	)
 
 	return err
 }
 
+// RunOnce runs a function right away, it is meant for the initial runs of
+// the jobs when the application starts.
+//
+// If a locker is set, the run is claimed for the startup window, so when
+// multiple instances start in the same deploy it happens only once.
+func (c *Cron) RunOnce(name string, function func()) {
+	c.lockerMu.RLock()
+	locker := c.locker
+	c.lockerMu.RUnlock()
+
+	if locker != nil {
+		key := JobID("startup:" + name).String()
+		claimed, err := locker.ClaimStartupRun(
+			context.Background(), key, startupRunWindow,
+		)
+		if err != nil {
+			logger.Error("error claiming startup run", logger.KV{
+				"job":   name,
+				"error": err,
+			})
+			return
+		}
+		if !claimed {
+			return
+		}
+	}
+
+	function()
+}
+
 // RemoveJob removes a job from the scheduler only if it exists.
 func (c *Cron) RemoveJob(id uuid.UUID) error {
 	jobs := c.scheduler.Jobs()
@@ -70,3 +165,44 @@ func (c *Cron) Start() {
 func (c *Cron) Shutdown() error {
 	return c.scheduler.Shutdown()
 }
+
+// JobID returns a stable job id for the given name, so the same job has the
+// same id in all the instances of the application.
+func JobID(name string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("pgbackweb:job:"+name))
+}
+
+var errRunAlreadyClaimed = errors.New("run already claimed by another instance")
+
+// runLocker adapts a Locker to the gocron.Locker interface.
+//
+// Cron expressions have a resolution of one minute, so the run is identified
+// by the time rounded to the minute, which tolerates small clock skews
+// between the instances.
+type runLocker struct {
+	locker Locker
+}
+
+func (l runLocker) Lock(ctx context.Context, key string) (gocron.Lock, error) {
+	claimed, err := l.locker.ClaimRun(ctx, key, time.Now().Round(time.Minute))
+	if err != nil {
+		logger.Error("error claiming scheduled run", logger.KV{
+			"job":   key,
+			"error": err,
+		})
+		return nil, err
+	}
+	if !claimed {
+		return nil, errRunAlreadyClaimed
+	}
+
+	return runLock{}, nil
+}
+
+// runLock is a no-op lock, the claim of a run is kept after the job finishes
+// so other instances don't run it again.
+type runLock struct{}
+
+func (runLock) Unlock(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE destinations
+ADD COLUMN IF NOT EXISTS is_local BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS local_path TEXT;
+
+ALTER TABLE destinations ADD CONSTRAINT destinations_local_path_check CHECK (
+  (is_local = TRUE AND local_path IS NOT NULL) OR
+  (is_local = FALSE AND local_path IS NULL)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE destinations DROP CONSTRAINT IF EXISTS destinations_local_path_check;
+
+ALTER TABLE destinations
+DROP COLUMN IF EXISTS is_local,
+DROP COLUMN IF EXISTS local_path;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE destinations
+ADD COLUMN IF NOT EXISTS use_path_style BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN IF NOT EXISTS storage_class TEXT,
+ADD COLUMN IF NOT EXISTS sse TEXT,
+ADD COLUMN IF NOT EXISTS sse_kms_key_id TEXT,
+ADD COLUMN IF NOT EXISTS object_lock_mode TEXT,
+ADD COLUMN IF NOT EXISTS object_lock_days INTEGER;
+
+ALTER TABLE destinations ADD CONSTRAINT destinations_sse_check CHECK (
+  sse IN ('AES256', 'aws:kms')
+);
+
+ALTER TABLE destinations ADD CONSTRAINT destinations_object_lock_check CHECK (
+  (object_lock_mode IS NULL AND object_lock_days IS NULL) OR
+  (
+    object_lock_mode IN ('GOVERNANCE', 'COMPLIANCE') AND
+    object_lock_days IS NOT NULL AND object_lock_days > 0
+  )
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE destinations DROP CONSTRAINT IF EXISTS destinations_sse_check;
+ALTER TABLE destinations DROP CONSTRAINT IF EXISTS destinations_object_lock_check;
+
+ALTER TABLE destinations
+DROP COLUMN IF EXISTS use_path_style,
+DROP COLUMN IF EXISTS storage_class,
+DROP COLUMN IF EXISTS sse,
+DROP COLUMN IF EXISTS sse_kms_key_id,
+DROP COLUMN IF EXISTS object_lock_mode,
+DROP COLUMN IF EXISTS object_lock_days;
+-- +goose StatementEnd
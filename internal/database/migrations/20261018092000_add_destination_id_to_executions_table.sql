@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE executions
+ADD COLUMN IF NOT EXISTS destination_id UUID
+REFERENCES destinations(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS
+idx_executions_destination_id ON executions(destination_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_executions_destination_id;
+
+ALTER TABLE executions DROP COLUMN IF EXISTS destination_id;
+-- +goose StatementEnd
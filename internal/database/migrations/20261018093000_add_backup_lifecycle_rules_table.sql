@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS backup_lifecycle_rules (
+  id UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+  backup_id UUID NOT NULL REFERENCES backups(id) ON DELETE CASCADE,
+  destination_id UUID NOT NULL REFERENCES destinations(id) ON DELETE CASCADE,
+
+  after_days SMALLINT NOT NULL CHECK (after_days > 0),
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  UNIQUE (backup_id, after_days)
+);
+
+CREATE INDEX IF NOT EXISTS
+idx_backup_lifecycle_rules_backup_id ON backup_lifecycle_rules(backup_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS backup_lifecycle_rules;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE destinations
+ADD COLUMN IF NOT EXISTS upload_rate_limit INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS upload_part_size INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS upload_concurrency INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE destinations ADD CONSTRAINT destinations_upload_limits_check CHECK (
+  upload_rate_limit >= 0 AND
+  (upload_part_size = 0 OR upload_part_size BETWEEN 5 AND 5120) AND
+  upload_concurrency BETWEEN 0 AND 64
+);
+
+ALTER TABLE backups
+ADD COLUMN IF NOT EXISTS dump_rate_limit INTEGER NOT NULL DEFAULT 0
+CHECK (dump_rate_limit >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE destinations DROP CONSTRAINT IF EXISTS destinations_upload_limits_check;
+
+ALTER TABLE destinations
+DROP COLUMN IF EXISTS upload_rate_limit,
+DROP COLUMN IF EXISTS upload_part_size,
+DROP COLUMN IF EXISTS upload_concurrency;
+
+ALTER TABLE backups DROP COLUMN IF EXISTS dump_rate_limit;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE executions
+ADD COLUMN IF NOT EXISTS upload_parts INTEGER,
+ADD COLUMN IF NOT EXISTS upload_retries INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE executions
+DROP COLUMN IF EXISTS upload_parts,
+DROP COLUMN IF EXISTS upload_retries;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE destinations
+ADD COLUMN IF NOT EXISTS free_space BIGINT,
+ADD COLUMN IF NOT EXISTS low_space_threshold INTEGER NOT NULL DEFAULT 0
+CHECK (low_space_threshold >= 0);
+
+ALTER TABLE webhooks DROP CONSTRAINT IF EXISTS webhooks_event_type_check;
+ALTER TABLE webhooks ADD CONSTRAINT webhooks_event_type_check CHECK (
+  event_type IN (
+    'database_healthy', 'database_unhealthy',
+    'destination_healthy', 'destination_unhealthy',
+    'execution_success', 'execution_failed',
+    'storage_low_space'
+  )
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM webhooks WHERE event_type = 'storage_low_space';
+
+ALTER TABLE webhooks DROP CONSTRAINT IF EXISTS webhooks_event_type_check;
+ALTER TABLE webhooks ADD CONSTRAINT webhooks_event_type_check CHECK (
+  event_type IN (
+    'database_healthy', 'database_unhealthy',
+    'destination_healthy', 'destination_unhealthy',
+    'execution_success', 'execution_failed'
+  )
+);
+
+ALTER TABLE destinations
+DROP COLUMN IF EXISTS free_space,
+DROP COLUMN IF EXISTS low_space_threshold;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+ADD COLUMN IF NOT EXISTS keep_daily SMALLINT NOT NULL DEFAULT 0 CHECK (keep_daily >= 0),
+ADD COLUMN IF NOT EXISTS keep_weekly SMALLINT NOT NULL DEFAULT 0 CHECK (keep_weekly >= 0),
+ADD COLUMN IF NOT EXISTS keep_monthly SMALLINT NOT NULL DEFAULT 0 CHECK (keep_monthly >= 0),
+ADD COLUMN IF NOT EXISTS keep_yearly SMALLINT NOT NULL DEFAULT 0 CHECK (keep_yearly >= 0);
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS gfs_keeper TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE executions DROP COLUMN IF EXISTS gfs_keeper;
+
+ALTER TABLE backups
+DROP COLUMN IF EXISTS keep_daily,
+DROP COLUMN IF EXISTS keep_weekly,
+DROP COLUMN IF EXISTS keep_monthly,
+DROP COLUMN IF EXISTS keep_yearly;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+ADD COLUMN IF NOT EXISTS keep_last SMALLINT NOT NULL DEFAULT 0 CHECK (keep_last >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backups DROP COLUMN IF EXISTS keep_last;
+-- +goose StatementEnd
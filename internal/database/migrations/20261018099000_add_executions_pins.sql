@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE executions
+ADD COLUMN IF NOT EXISTS pin_type TEXT CHECK (pin_type IN ('pin', 'legal_hold')),
+ADD COLUMN IF NOT EXISTS pin_reason TEXT,
+ADD COLUMN IF NOT EXISTS pinned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_executions_pinned_at ON executions(pinned_at)
+WHERE pinned_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_executions_pinned_at;
+
+ALTER TABLE executions
+DROP COLUMN IF EXISTS pin_type,
+DROP COLUMN IF EXISTS pin_reason,
+DROP COLUMN IF EXISTS pinned_by,
+DROP COLUMN IF EXISTS pinned_at;
+-- +goose StatementEnd
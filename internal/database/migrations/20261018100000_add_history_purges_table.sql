@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS history_purges (
+  id UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+
+  threshold TIMESTAMPTZ NOT NULL,
+  executions_deleted BIGINT NOT NULL DEFAULT 0,
+  restorations_deleted BIGINT NOT NULL DEFAULT 0,
+  webhook_executions_deleted BIGINT NOT NULL DEFAULT 0,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS
+idx_history_purges_created_at ON history_purges(created_at);
+
+CREATE INDEX IF NOT EXISTS
+idx_webhook_executions_created_at ON webhook_executions(created_at);
+
+CREATE INDEX IF NOT EXISTS
+idx_executions_deleted_at ON executions(deleted_at)
+WHERE status = 'deleted';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_executions_deleted_at;
+DROP INDEX IF EXISTS idx_webhook_executions_created_at;
+DROP TABLE IF EXISTS history_purges;
+-- +goose StatementEnd
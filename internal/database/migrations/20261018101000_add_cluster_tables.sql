@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS cron_run_claims (
+  job_key TEXT NOT NULL,
+  scheduled_at TIMESTAMPTZ NOT NULL,
+  node_id TEXT NOT NULL,
+  claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (job_key, scheduled_at)
+);
+
+CREATE INDEX IF NOT EXISTS
+idx_cron_run_claims_claimed_at ON cron_run_claims(claimed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS cron_run_claims;
+-- +goose StatementEnd
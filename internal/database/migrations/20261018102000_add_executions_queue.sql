@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE executions DROP CONSTRAINT IF EXISTS executions_status_check;
+ALTER TABLE executions ADD CONSTRAINT executions_status_check CHECK (
+  status IN ('queued', 'running', 'success', 'failed', 'deleted')
+);
+
+ALTER TABLE executions
+ADD COLUMN IF NOT EXISTS queued_at TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS node_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_executions_queue ON executions(queued_at)
+WHERE status IN ('queued', 'running');
+
+ALTER TABLE destinations
+ADD COLUMN IF NOT EXISTS max_running_executions INTEGER NOT NULL DEFAULT 0
+CHECK (max_running_executions >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE destinations DROP COLUMN IF EXISTS max_running_executions;
+
+DROP INDEX IF EXISTS idx_executions_queue;
+
+ALTER TABLE executions
+DROP COLUMN IF EXISTS queued_at,
+DROP COLUMN IF EXISTS node_id;
+
+UPDATE executions SET status = 'failed' WHERE status = 'queued';
+ALTER TABLE executions DROP CONSTRAINT IF EXISTS executions_status_check;
+ALTER TABLE executions ADD CONSTRAINT executions_status_check CHECK (
+  status IN ('running', 'success', 'failed', 'deleted')
+);
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+ADD COLUMN IF NOT EXISTS catch_up_policy TEXT NOT NULL DEFAULT 'skip'
+CHECK (catch_up_policy IN ('skip', 'once', 'all'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backups DROP COLUMN IF EXISTS catch_up_policy;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS cluster_nodes (
+  node_id TEXT NOT NULL PRIMARY KEY,
+  started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE restorations ADD COLUMN IF NOT EXISTS node_id TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE restorations DROP COLUMN IF EXISTS node_id;
+
+DROP TABLE IF EXISTS cluster_nodes;
+-- +goose StatementEnd
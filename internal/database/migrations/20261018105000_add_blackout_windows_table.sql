@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS blackout_windows (
+  id UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+  database_id UUID REFERENCES databases(id) ON DELETE CASCADE,
+  name TEXT NOT NULL CHECK (name <> ''),
+  action TEXT NOT NULL CHECK (action IN ('skip', 'defer')),
+
+  starts_at TIMESTAMPTZ,
+  ends_at TIMESTAMPTZ,
+
+  cron_expression TEXT,
+  time_zone TEXT,
+  duration_minutes INTEGER CHECK (duration_minutes > 0),
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ,
+
+  CHECK (
+    (
+      starts_at IS NOT NULL AND ends_at IS NOT NULL AND ends_at > starts_at
+      AND cron_expression IS NULL
+    ) OR (
+      cron_expression IS NOT NULL AND time_zone IS NOT NULL
+      AND duration_minutes IS NOT NULL
+      AND starts_at IS NULL AND ends_at IS NULL
+    )
+  )
+);
+
+CREATE INDEX IF NOT EXISTS idx_blackout_windows_database_id
+ON blackout_windows(database_id);
+
+CREATE TRIGGER blackout_windows_change_updated_at
+BEFORE UPDATE ON blackout_windows FOR EACH ROW EXECUTE FUNCTION change_updated_at();
+
+ALTER TABLE executions DROP CONSTRAINT IF EXISTS executions_status_check;
+ALTER TABLE executions ADD CONSTRAINT executions_status_check CHECK (
+  status IN ('queued', 'running', 'success', 'failed', 'skipped', 'deleted')
+);
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS deferred_until TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE executions DROP COLUMN IF EXISTS deferred_until;
+
+UPDATE executions SET status = 'failed' WHERE status = 'skipped';
+ALTER TABLE executions DROP CONSTRAINT IF EXISTS executions_status_check;
+ALTER TABLE executions ADD CONSTRAINT executions_status_check CHECK (
+  status IN ('queued', 'running', 'success', 'failed', 'deleted')
+);
+
+DROP TABLE IF EXISTS blackout_windows;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+ADD COLUMN IF NOT EXISTS run_after_backup_id UUID
+REFERENCES backups(id) ON DELETE SET NULL
+CHECK (run_after_backup_id <> id),
+ADD COLUMN IF NOT EXISTS run_after_condition TEXT NOT NULL DEFAULT 'success'
+CHECK (run_after_condition IN ('success', 'always'));
+
+CREATE INDEX IF NOT EXISTS idx_backups_run_after_backup_id
+ON backups(run_after_backup_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backups_run_after_backup_id;
+
+ALTER TABLE backups
+DROP COLUMN IF EXISTS run_after_backup_id,
+DROP COLUMN IF EXISTS run_after_condition;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE executions
+ADD COLUMN IF NOT EXISTS is_upload BOOLEAN NOT NULL DEFAULT false;
+
+-- Uploaded dumps were stored in a "uploads" directory with an "upload-"
+-- prefix before this column existed
+UPDATE executions
+SET is_upload = true
+WHERE path ~ '(^|/)uploads/[0-9]{4}/[0-9]{2}/[0-9]{2}/upload-[^/]+$';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE executions DROP COLUMN IF EXISTS is_upload;
+-- +goose StatementEnd
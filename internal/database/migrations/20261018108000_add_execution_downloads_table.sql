@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS execution_downloads (
+  id UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+  execution_id UUID NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  user_email TEXT NOT NULL,
+  ip TEXT NOT NULL,
+  mode TEXT NOT NULL CHECK (mode IN ('local', 'proxy', 'presigned')),
+  byte_range TEXT,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_downloads_execution_id
+ON execution_downloads(execution_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS execution_downloads;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE executions
+ADD COLUMN IF NOT EXISTS lifecycle_attempts INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS lifecycle_error TEXT,
+ADD COLUMN IF NOT EXISTS lifecycle_retry_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE executions
+DROP COLUMN IF EXISTS lifecycle_attempts,
+DROP COLUMN IF EXISTS lifecycle_error,
+DROP COLUMN IF EXISTS lifecycle_retry_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The existing executions were stored in the destination of their backup
+UPDATE executions
+SET destination_id = backups.destination_id
+FROM backups
+WHERE backups.id = executions.backup_id
+AND executions.destination_id IS NULL
+AND backups.destination_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- The backfilled values are kept, they are dropped with the column
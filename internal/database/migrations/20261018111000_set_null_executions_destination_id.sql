@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleting a destination must not delete the executions moved to another
+-- destination through their backup, and the executions moved away from a
+-- destination must not block its deletion
+ALTER TABLE executions
+DROP CONSTRAINT IF EXISTS executions_destination_id_fkey,
+ADD CONSTRAINT executions_destination_id_fkey
+FOREIGN KEY (destination_id) REFERENCES destinations(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE executions
+DROP CONSTRAINT IF EXISTS executions_destination_id_fkey,
+ADD CONSTRAINT executions_destination_id_fkey
+FOREIGN KEY (destination_id) REFERENCES destinations(id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A backup that others run after can't be deleted, setting their parent to
+-- NULL would turn them into cron backups
+ALTER TABLE backups
+DROP CONSTRAINT IF EXISTS backups_run_after_backup_id_fkey,
+ADD CONSTRAINT backups_run_after_backup_id_fkey
+FOREIGN KEY (run_after_backup_id) REFERENCES backups(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backups
+DROP CONSTRAINT IF EXISTS backups_run_after_backup_id_fkey,
+ADD CONSTRAINT backups_run_after_backup_id_fkey
+FOREIGN KEY (run_after_backup_id) REFERENCES backups(id) ON DELETE SET NULL;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every part being uploaded is held in memory, the part size × (concurrency
+-- + 1) of a destination is limited to 2048 MiB (0 means the defaults, 5 MiB
+-- parts and 5 parts at the same time)
+UPDATE destinations
+SET upload_part_size = 1024
+WHERE upload_part_size > 1024;
+
+UPDATE destinations
+SET upload_concurrency = GREATEST(
+  1, 2048 / COALESCE(NULLIF(upload_part_size, 0), 5) - 1
+)
+WHERE COALESCE(NULLIF(upload_part_size, 0), 5) *
+  (COALESCE(NULLIF(upload_concurrency, 0), 5) + 1) > 2048;
+
+ALTER TABLE destinations
+ADD CONSTRAINT destinations_upload_memory_check CHECK (
+  COALESCE(NULLIF(upload_part_size, 0), 5) *
+  (COALESCE(NULLIF(upload_concurrency, 0), 5) + 1) <= 2048
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE destinations
+DROP CONSTRAINT IF EXISTS destinations_upload_memory_check;
+-- +goose StatementEnd
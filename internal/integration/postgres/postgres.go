@@ -3,11 +3,16 @@ package postgres
 import (
 	"archive/zip"
 	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/eduardolat/pgbackweb/internal/util/rateutil"
 	"github.com/eduardolat/pgbackweb/internal/util/strutil"
 	"github.com/orsinium-labs/enum"
 )
@@ -21,38 +26,44 @@ import (
 */
 
 type version struct {
-	Version string
-	PGDump  string
-	PSQL    string
+	Version   string
+	PGDump    string
+	PGRestore string
+	PSQL      string
 }
 
 type PGVersion enum.Member[version]
 
 var (
 	PG13 = PGVersion{version{
-		Version: "13",
-		PGDump:  "/usr/lib/postgresql/13/bin/pg_dump",
-		PSQL:    "/usr/lib/postgresql/13/bin/psql",
+		Version:   "13",
+		PGDump:    "/usr/lib/postgresql/13/bin/pg_dump",
+		PGRestore: "/usr/lib/postgresql/13/bin/pg_restore",
+		PSQL:      "/usr/lib/postgresql/13/bin/psql",
 	}}
 	PG14 = PGVersion{version{
-		Version: "14",
-		PGDump:  "/usr/lib/postgresql/14/bin/pg_dump",
-		PSQL:    "/usr/lib/postgresql/14/bin/psql",
+		Version:   "14",
+		PGDump:    "/usr/lib/postgresql/14/bin/pg_dump",
+		PGRestore: "/usr/lib/postgresql/14/bin/pg_restore",
+		PSQL:      "/usr/lib/postgresql/14/bin/psql",
 	}}
 	PG15 = PGVersion{version{
-		Version: "15",
-		PGDump:  "/usr/lib/postgresql/15/bin/pg_dump",
-		PSQL:    "/usr/lib/postgresql/15/bin/psql",
+		Version:   "15",
+		PGDump:    "/usr/lib/postgresql/15/bin/pg_dump",
+		PGRestore: "/usr/lib/postgresql/15/bin/pg_restore",
+		PSQL:      "/usr/lib/postgresql/15/bin/psql",
 	}}
 	PG16 = PGVersion{version{
-		Version: "16",
-		PGDump:  "/usr/lib/postgresql/16/bin/pg_dump",
-		PSQL:    "/usr/lib/postgresql/16/bin/psql",
+		Version:   "16",
+		PGDump:    "/usr/lib/postgresql/16/bin/pg_dump",
+		PGRestore: "/usr/lib/postgresql/16/bin/pg_restore",
+		PSQL:      "/usr/lib/postgresql/16/bin/psql",
 	}}
 	PG17 = PGVersion{version{
-		Version: "17",
-		PGDump:  "/usr/lib/postgresql/17/bin/pg_dump",
-		PSQL:    "/usr/lib/postgresql/17/bin/psql",
+		Version:   "17",
+		PGDump:    "/usr/lib/postgresql/17/bin/pg_dump",
+		PGRestore: "/usr/lib/postgresql/17/bin/pg_restore",
+		PSQL:      "/usr/lib/postgresql/17/bin/psql",
 	}}
 
 	PGVersions = []PGVersion{PG13, PG14, PG15, PG16, PG17}
@@ -127,6 +138,10 @@ type DumpParams struct {
 
 	// NoComments (--no-comments): Do not dump comments.
 	NoComments bool
+
+	// ReadRateLimit is the maximum number of bytes per second read from the
+	// pg_dump output. If 0 the output is read as fast as possible.
+	ReadRateLimit int64
 }
 
 // Dump runs the pg_dump command with the given parameters. It returns the SQL
@@ -175,7 +190,7 @@ func (Client) Dump(
 		}
 	}()
 
-	return reader
+	return rateutil.LimitReader(reader, pickedParams.ReadRateLimit)
 }
 
 // DumpZip runs the pg_dump command with the given parameters and returns the
@@ -207,58 +222,99 @@ func (c *Client) DumpZip(
 	return reader
 }
 
-// RestoreZip downloads or copies the ZIP from the given url or path, unzips it,
-// and runs the psql command to restore the database.
+// Restore downloads or copies the dump from the given url or path and
+// restores it into the database.
+//
+// The format of the dump is detected from the file name and its content:
+//
+//   - .zip: must contain a dump.sql file, it is restored using psql
+//   - .gz: it is decompressed and restored as a plain or custom dump
+//   - custom format (pg_dump -Fc): it is restored using pg_restore
+//   - anything else is treated as a plain SQL dump and restored using psql
 //
-// The ZIP file must contain a dump.sql file with the SQL dump to restore.
+// Parameters:
 //
 //   - version: PostgreSQL version to use for the restore
 //   - connString: connection string to the database
-//   - isLocal: whether the ZIP file is local or a URL
-//   - zipURLOrPath: URL or path to the ZIP file
-func (Client) RestoreZip(
-	version PGVersion, connString string, isLocal bool, zipURLOrPath string,
+//   - isLocal: whether the dump file is local or a URL
+//   - dumpURLOrPath: URL or path to the dump file
+//   - fileName: name of the dump file, used to detect the format
+func (Client) Restore(
+	version PGVersion, connString string, isLocal bool,
+	dumpURLOrPath string, fileName string,
 ) error {
 	workDir, err := os.MkdirTemp("", "pbw-restore-*")
 	if err != nil {
 		return fmt.Errorf("error creating temp dir: %w", err)
 	}
 	defer os.RemoveAll(workDir)
-	zipPath := strutil.CreatePath(true, workDir, "dump.zip")
+	filePath := strutil.CreatePath(true, workDir, "dump.file")
 	dumpPath := strutil.CreatePath(true, workDir, "dump.sql")
 
 	if isLocal {
-		cmd := exec.Command("cp", zipURLOrPath, zipPath)
+		cmd := exec.Command("cp", dumpURLOrPath, filePath)
 		output, err := cmd.CombinedOutput()
 		if err != nil {
-			return fmt.Errorf("error copying ZIP file to temp dir: %s", output)
+			return fmt.Errorf("error copying dump file to temp dir: %s", output)
 		}
 	}
 
 	if !isLocal {
-		cmd := exec.Command("wget", "--no-verbose", "-O", zipPath, zipURLOrPath)
+		cmd := exec.Command("wget", "--no-verbose", "-O", filePath, dumpURLOrPath)
 		output, err := cmd.CombinedOutput()
 		if err != nil {
-			return fmt.Errorf("error downloading ZIP file: %s", output)
+			return fmt.Errorf("error downloading dump file: %s", output)
 		}
 	}
 
-	if _, err := os.Stat(zipPath); os.IsNotExist(err) {
-		return fmt.Errorf("zip file not found: %s", zipPath)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return fmt.Errorf("dump file not found: %s", filePath)
 	}
 
-	cmd := exec.Command("unzip", "-o", zipPath, "dump.sql", "-d", workDir)
-	output, err := cmd.CombinedOutput()
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".zip":
+		unzipDir := strutil.CreatePath(true, workDir, "unzipped")
+		cmd := exec.Command("unzip", "-o", filePath, "-d", unzipDir)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error unzipping ZIP file: %s", output)
+		}
+
+		dumpPath, err = findZipDump(unzipDir)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, fileName)
+		}
+	case ".gz":
+		if err := gunzipFile(filePath, dumpPath); err != nil {
+			return err
+		}
+	default:
+		dumpPath = filePath
+	}
+
+	isCustom, err := isCustomFormatDump(dumpPath)
 	if err != nil {
-		return fmt.Errorf("error unzipping ZIP file: %s", output)
+		return err
 	}
 
-	if _, err := os.Stat(dumpPath); os.IsNotExist(err) {
-		return fmt.Errorf("dump.sql file not found in ZIP file: %s", zipPath)
+	if isCustom {
+		cmd := exec.Command(
+			version.Value.PGRestore, "--dbname", connString,
+			"--no-owner", "--no-privileges", dumpPath,
+		)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf(
+				"error running pg_restore v%s command: %s",
+				version.Value.Version, output,
+			)
+		}
+
+		return nil
 	}
 
-	cmd = exec.Command(version.Value.PSQL, connString, "-f", dumpPath)
-	output, err = cmd.CombinedOutput()
+	cmd := exec.Command(version.Value.PSQL, connString, "-f", dumpPath)
+	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf(
 			"error running psql v%s command: %s",
@@ -268,3 +324,78 @@ func (Client) RestoreZip(
 
 	return nil
 }
+
+// findZipDump returns the path of the dump extracted from a ZIP file. It
+// prefers the dump.sql file created by PG Back Web and falls back to the
+// only file in the ZIP.
+func findZipDump(unzipDir string) (string, error) {
+	dumpPath := strutil.CreatePath(true, unzipDir, "dump.sql")
+	if _, err := os.Stat(dumpPath); err == nil {
+		return dumpPath, nil
+	}
+
+	files := []string{}
+	err := filepath.WalkDir(unzipDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error reading unzipped files: %w", err)
+	}
+
+	if len(files) != 1 {
+		return "", errors.New("dump.sql or a single dump file not found in ZIP file")
+	}
+
+	return files[0], nil
+}
+
+// gunzipFile decompresses the gzip file in src into dst.
+func gunzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("error opening gzip file: %w", err)
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("error reading gzip file: %w", err)
+	}
+	defer gz.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("error creating decompressed file: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, gz); err != nil {
+		return fmt.Errorf("error decompressing gzip file: %w", err)
+	}
+
+	return nil
+}
+
+// isCustomFormatDump returns true if the file is a pg_dump custom format
+// archive, which always starts with the PGDMP magic string.
+func isCustomFormatDump(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("error opening dump file: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 5)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("error reading dump file: %w", err)
+	}
+
+	return bytes.Equal(header[:n], []byte("PGDMP")), nil
+}
@@ -1,10 +1,13 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"syscall"
 
 	"github.com/eduardolat/pgbackweb/internal/util/strutil"
 )
@@ -13,12 +16,63 @@ const (
 	localBackupsDir string = "/backups"
 )
 
+// localBasePath returns the provided base path or the default local backups
+// directory if it is empty.
+func localBasePath(basePath string) string {
+	if basePath == "" {
+		return localBackupsDir
+	}
+	return basePath
+}
+
+// LocalTest tests that the provided base path is an existing and writable
+// directory.
+//
+// Returns the free space available in the directory, in bytes.
+func (*Client) LocalTest(basePath string) (int64, error) {
+	basePath = localBasePath(basePath)
+	if !filepath.IsAbs(basePath) {
+		return 0, fmt.Errorf("path %s must be absolute", basePath)
+	}
+
+	info, err := os.Stat(basePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat path %s: %w", basePath, err)
+	}
+	if !info.IsDir() {
+		return 0, fmt.Errorf("path %s is not a directory", basePath)
+	}
+
+	testFile, err := os.CreateTemp(basePath, ".pbw-test-*")
+	if err != nil {
+		return 0, fmt.Errorf("path %s is not writable: %w", basePath, err)
+	}
+	testFile.Close()
+	if err := os.Remove(testFile.Name()); err != nil {
+		return 0, fmt.Errorf("failed to remove test file %s: %w", testFile.Name(), err)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(basePath, &stat); err != nil {
+		return 0, fmt.Errorf("failed to get free space of %s: %w", basePath, err)
+	}
+
+	freeBytes := int64(stat.Bavail) * int64(stat.Bsize)
+	if freeBytes <= 0 {
+		return 0, errors.New("no free space left in " + basePath)
+	}
+
+	return freeBytes, nil
+}
+
 // LocalUpload Creates a new file using the provided path and reader relative
-// to the local backups directory.
+// to the provided base path (or the default local backups directory if empty).
 //
 // Returns the size of the file created, in bytes.
-func (Client) LocalUpload(relativeFilePath string, fileReader io.Reader) (int64, error) {
-	fullPath := strutil.CreatePath(true, localBackupsDir, relativeFilePath)
+func (*Client) LocalUpload(
+	basePath string, relativeFilePath string, fileReader io.Reader,
+) (int64, error) {
+	fullPath := strutil.CreatePath(true, localBasePath(basePath), relativeFilePath)
 	dir := filepath.Dir(fullPath)
 
 	err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm)
@@ -45,10 +99,58 @@ func (Client) LocalUpload(relativeFilePath string, fileReader io.Reader) (int64,
 	return fileInfo.Size(), nil
 }
 
-// LocalDelete Deletes a file using the provided path relative to the local
-// backups directory.
-func (Client) LocalDelete(relativeFilePath string) error {
-	fullPath := strutil.CreatePath(true, localBackupsDir, relativeFilePath)
+// LocalListFiles lists all the files stored under the provided directory
+// relative to the provided base path (or the default local backups directory
+// if empty).
+//
+// The returned paths are relative to the base path. If the directory doesn't
+// exist an empty list is returned.
+func (*Client) LocalListFiles(
+	basePath string, relativeDir string,
+) ([]FileInfo, error) {
+	basePath = localBasePath(basePath)
+	root := strutil.CreatePath(true, basePath, relativeDir)
+
+	files := []FileInfo{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(basePath, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, FileInfo{
+			Path:       filepath.ToSlash(relPath),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", root, err)
+	}
+
+	return files, nil
+}
+
+// LocalDelete Deletes a file using the provided path relative to the provided
+// base path (or the default local backups directory if empty).
+func (*Client) LocalDelete(basePath string, relativeFilePath string) error {
+	fullPath := strutil.CreatePath(true, localBasePath(basePath), relativeFilePath)
 
 	err := os.Remove(fullPath)
 	if err != nil {
@@ -59,7 +161,8 @@ func (Client) LocalDelete(relativeFilePath string) error {
 }
 
 // LocalGetFullPath Returns the full path of a file using the provided relative
-// file path to the local backups directory.
-func (Client) LocalGetFullPath(relativeFilePath string) string {
-	return strutil.CreatePath(true, localBackupsDir, relativeFilePath)
+// file path to the provided base path (or the default local backups directory
+// if empty).
+func (*Client) LocalGetFullPath(basePath string, relativeFilePath string) string {
+	return strutil.CreatePath(true, localBasePath(basePath), relativeFilePath)
 }
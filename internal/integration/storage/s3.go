@@ -2,21 +2,58 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/eduardolat/pgbackweb/internal/util/strutil"
 )
 
-// createS3Client creates a new S3 client
+// S3UploadOptions are the optional destination level settings applied to
+// the objects uploaded to S3. Empty values use the bucket defaults.
+type S3UploadOptions struct {
+	// StorageClass is the storage class of the object, e.g. STANDARD_IA or
+	// GLACIER_IR.
+	StorageClass string
+	// SSE is the server-side encryption algorithm, AES256 (SSE-S3) or
+	// aws:kms (SSE-KMS).
+	SSE string
+	// SSEKMSKeyID is the KMS key id used when SSE is aws:kms.
+	SSEKMSKeyID string
+	// ObjectLockMode is the object lock retention mode, GOVERNANCE or
+	// COMPLIANCE. The bucket must have object lock enabled.
+	ObjectLockMode string
+	// ObjectLockDays is the number of days the object is locked.
+	ObjectLockDays int
+	// PartSizeMB is the size of each part of the multipart upload, in MiB.
+	// If 0 the uploader default (5 MiB) is used. Parts smaller than 64 MiB
+	// grow up to 64 MiB for big streams, within S3MaxUploadMemoryMB.
+	PartSizeMB int
+	// Concurrency is the number of parts uploaded in parallel. If 0 the
+	// uploader default (5) is used. The peak memory of an upload is the part
+	// size × (concurrency + 1), at most S3MaxUploadMemoryMB.
+	Concurrency int
+	// RetryWindow is the time during which a failed part keeps being retried,
+	// measured from its first failure. If 0 failed parts are not retried.
+	RetryWindow time.Duration
+}
+
+// createS3Client creates a new S3 client.
+//
+// If usePathStyle is true the bucket is sent in the path of the request
+// (endpoint/bucket/key), otherwise virtual-hosted addressing is used
+// (bucket.endpoint/key).
 func createS3Client(
-	accessKey, secretKey, region, endpoint string,
+	accessKey, secretKey, region, endpoint string, usePathStyle bool,
 ) (*s3.Client, error) {
 	credentialsProvider := credentials.NewStaticCredentialsProvider(
 		accessKey, secretKey, "",
@@ -27,7 +64,7 @@ func createS3Client(
 		_ string, _ string,
 	) (aws.Endpoint, error) {
 		return aws.Endpoint{
-			HostnameImmutable: true,
+			HostnameImmutable: usePathStyle,
 			URL:               endpoint,
 		}, nil
 	})
@@ -43,16 +80,88 @@ func createS3Client(
 		return nil, fmt.Errorf("error initializing storage config: %w", err)
 	}
 
-	s3Client := s3.NewFromConfig(conf)
+	s3Client := s3.NewFromConfig(conf, func(o *s3.Options) {
+		o.UsePathStyle = usePathStyle
+	})
 	return s3Client, nil
 }
 
+// s3ClientKey returns the key used to cache a S3 client with the given
+// connection settings.
+func s3ClientKey(
+	accessKey, secretKey, region, endpoint string, usePathStyle bool,
+) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		accessKey, secretKey, region, endpoint, strconv.FormatBool(usePathStyle),
+	}, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// getS3Client returns the cached S3 client for the given connection settings
+// or creates a new one if it doesn't exist.
+func (c *Client) getS3Client(
+	accessKey, secretKey, region, endpoint string, usePathStyle bool,
+) (*s3.Client, error) {
+	key := s3ClientKey(accessKey, secretKey, region, endpoint, usePathStyle)
+
+	c.s3ClientsMu.Lock()
+	defer c.s3ClientsMu.Unlock()
+
+	if cached, ok := c.s3Clients[key]; ok {
+		cached.lastUsed = time.Now()
+		return cached.client, nil
+	}
+
+	s3Client, err := createS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Connection tests with settings that are never saved would otherwise
+	// grow the cache forever
+	if len(c.s3Clients) >= s3ClientsMax {
+		c.evictOldestS3Client()
+	}
+
+	c.s3Clients[key] = &s3CachedClient{client: s3Client, lastUsed: time.Now()}
+	return s3Client, nil
+}
+
+// evictOldestS3Client removes the least recently used S3 client from the
+// cache, the caller must hold s3ClientsMu.
+func (c *Client) evictOldestS3Client() {
+	var oldestKey string
+	var oldest time.Time
+	for key, cached := range c.s3Clients {
+		if oldestKey == "" || cached.lastUsed.Before(oldest) {
+			oldestKey = key
+			oldest = cached.lastUsed
+		}
+	}
+	delete(c.s3Clients, oldestKey)
+}
+
+// S3ForgetClient removes the cached S3 client for the given connection
+// settings. It should be called when a destination is updated or deleted.
+func (c *Client) S3ForgetClient(
+	accessKey, secretKey, region, endpoint string, usePathStyle bool,
+) {
+	key := s3ClientKey(accessKey, secretKey, region, endpoint, usePathStyle)
+
+	c.s3ClientsMu.Lock()
+	defer c.s3ClientsMu.Unlock()
+
+	delete(c.s3Clients, key)
+}
+
 // S3Test tests the connection to S3
-func (Client) S3Test(
-	accessKey, secretKey, region, endpoint, bucketName string,
+func (c *Client) S3Test(
+	accessKey, secretKey, region, endpoint, bucketName string, usePathStyle bool,
 ) error {
-	s3Client, err := createS3Client(
-		accessKey, secretKey, region, endpoint,
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
 	)
 	if err != nil {
 		return err
@@ -71,62 +180,151 @@ func (Client) S3Test(
 	return nil
 }
 
-// S3Upload uploads a file to S3 from a reader.
+// S3TestObjectLock tests that the bucket has object lock enabled, it is
+// required to upload objects with a retention mode.
+func (c *Client) S3TestObjectLock(
+	accessKey, secretKey, region, endpoint, bucketName string, usePathStyle bool,
+) error {
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := s3Client.GetObjectLockConfiguration(
+		context.TODO(),
+		&s3.GetObjectLockConfigurationInput{
+			Bucket: aws.String(bucketName),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get S3 bucket object lock configuration: %w", err)
+	}
+
+	if res.ObjectLockConfiguration == nil ||
+		res.ObjectLockConfiguration.ObjectLockEnabled != types.ObjectLockEnabledEnabled {
+		return fmt.Errorf("object lock is not enabled in S3 bucket %s", bucketName)
+	}
+
+	return nil
+}
+
+// S3Upload uploads a file to S3 from a reader using a multipart upload with
+// part level retries.
 //
-// Returns the file size, in bytes.
-func (Client) S3Upload(
+// Returns the statistics of the upload, also when it fails.
+func (c *Client) S3Upload(
 	accessKey, secretKey, region, endpoint, bucketName, key string,
-	fileReader io.Reader,
-) (int64, error) {
-	s3Client, err := createS3Client(
-		accessKey, secretKey, region, endpoint,
+	usePathStyle bool, opts S3UploadOptions, fileReader io.Reader,
+) (UploadStats, error) {
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
 	)
 	if err != nil {
-		return 0, err
+		return UploadStats{}, err
 	}
 
 	key = strutil.RemoveLeadingSlash(key)
 	contentType := strutil.GetContentTypeFromFileName(key)
 
-	uploader := manager.NewUploader(s3Client)
-	_, err = uploader.Upload(
-		context.TODO(),
-		&s3.PutObjectInput{
-			Bucket:      aws.String(bucketName),
-			Key:         aws.String(key),
-			Body:        fileReader,
-			ContentType: aws.String(contentType),
-		},
-	)
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(key),
+		Body:        fileReader,
+		ContentType: aws.String(contentType),
+	}
+
+	if opts.StorageClass != "" {
+		input.StorageClass = types.StorageClass(opts.StorageClass)
+	}
+
+	if opts.SSE != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(opts.SSE)
+	}
+
+	if opts.SSE == string(types.ServerSideEncryptionAwsKms) && opts.SSEKMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(opts.SSEKMSKeyID)
+	}
+
+	if opts.ObjectLockMode != "" && opts.ObjectLockDays > 0 {
+		input.ObjectLockMode = types.ObjectLockMode(opts.ObjectLockMode)
+		input.ObjectLockRetainUntilDate = aws.Time(
+			time.Now().AddDate(0, 0, opts.ObjectLockDays),
+		)
+		// S3 requires an integrity checksum for objects with a retention period
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32
+	}
+
+	stats, err := s3MultipartUpload(context.TODO(), s3Client, input, opts)
 	if err != nil {
-		return 0, fmt.Errorf("failed to upload file to S3: %w", err)
+		return stats, fmt.Errorf("failed to upload file to S3: %w", err)
 	}
 
-	fileHead, err := s3Client.HeadObject(
-		context.TODO(),
-		&s3.HeadObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(key),
-		},
+	return stats, nil
+}
+
+// S3ListFiles lists all the files stored in S3 under the given prefix.
+func (c *Client) S3ListFiles(
+	accessKey, secretKey, region, endpoint, bucketName, prefix string,
+	usePathStyle bool,
+) ([]FileInfo, error) {
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get uploaded file info from S3: %w", err)
+		return nil, err
 	}
 
-	var fileSize int64
-	if fileHead.ContentLength != nil {
-		fileSize = *fileHead.ContentLength
+	prefix = strutil.RemoveLeadingSlash(prefix)
+	if prefix != "" {
+		prefix = strutil.RemoveTrailingSlash(prefix) + "/"
+	}
+
+	files := []FileInfo{}
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files from S3: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			if obj.Key == nil || strings.HasSuffix(*obj.Key, "/") {
+				continue
+			}
+
+			var size int64
+			if obj.Size != nil {
+				size = *obj.Size
+			}
+
+			var modifiedAt time.Time
+			if obj.LastModified != nil {
+				modifiedAt = *obj.LastModified
+			}
+
+			files = append(files, FileInfo{
+				Path:       *obj.Key,
+				Size:       size,
+				ModifiedAt: modifiedAt,
+			})
+		}
 	}
 
-	return fileSize, nil
+	return files, nil
 }
 
 // S3Delete deletes a file from S3
-func (Client) S3Delete(
+func (c *Client) S3Delete(
 	accessKey, secretKey, region, endpoint, bucketName, key string,
+	usePathStyle bool,
 ) error {
-	s3Client, err := createS3Client(
-		accessKey, secretKey, region, endpoint,
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
 	)
 	if err != nil {
 		return err
@@ -148,13 +346,110 @@ func (Client) S3Delete(
 	return nil
 }
 
+// S3AbortMultipartUploads aborts the incomplete multipart uploads of a key,
+// so the storage of their uploaded parts is freed.
+func (c *Client) S3AbortMultipartUploads(
+	accessKey, secretKey, region, endpoint, bucketName, key string,
+	usePathStyle bool,
+) error {
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
+	)
+	if err != nil {
+		return err
+	}
+
+	key = strutil.RemoveLeadingSlash(key)
+
+	paginator := s3.NewListMultipartUploadsPaginator(
+		s3Client, &s3.ListMultipartUploadsInput{
+			Bucket: aws.String(bucketName),
+			Prefix: aws.String(key),
+		},
+	)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return fmt.Errorf("failed to list multipart uploads from S3: %w", err)
+		}
+
+		for _, upload := range page.Uploads {
+			if upload.Key == nil || *upload.Key != key {
+				continue
+			}
+
+			_, err := s3Client.AbortMultipartUpload(
+				context.TODO(),
+				&s3.AbortMultipartUploadInput{
+					Bucket:   aws.String(bucketName),
+					Key:      upload.Key,
+					UploadId: upload.UploadId,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to abort multipart upload in S3: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// S3Object is an object downloaded from S3.
+type S3Object struct {
+	// Body is the content of the object, it must be closed by the caller.
+	Body io.ReadCloser
+	// ContentLength is the size of the returned body, in bytes.
+	ContentLength int64
+	// ContentRange is the range of the object returned when a byte range was
+	// requested, e.g. "bytes 0-99/1000".
+	ContentRange string
+}
+
+// S3Download downloads a file from S3. If byteRange is not empty (e.g.
+// "bytes=0-99") only that range of the file is returned.
+func (c *Client) S3Download(
+	accessKey, secretKey, region, endpoint, bucketName, key string,
+	usePathStyle bool, byteRange string,
+) (S3Object, error) {
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
+	)
+	if err != nil {
+		return S3Object{}, err
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(strutil.RemoveLeadingSlash(key)),
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+
+	out, err := s3Client.GetObject(context.TODO(), input)
+	if err != nil {
+		return S3Object{}, fmt.Errorf("failed to download file from S3: %w", err)
+	}
+
+	obj := S3Object{Body: out.Body}
+	if out.ContentLength != nil {
+		obj.ContentLength = *out.ContentLength
+	}
+	if out.ContentRange != nil {
+		obj.ContentRange = *out.ContentRange
+	}
+
+	return obj, nil
+}
+
 // S3GetDownloadLink generates a presigned URL for downloading a file from S3
-func (Client) S3GetDownloadLink(
+func (c *Client) S3GetDownloadLink(
 	accessKey, secretKey, region, endpoint, bucketName, key string,
-	expiration time.Duration,
+	usePathStyle bool, expiration time.Duration,
 ) (string, error) {
-	s3Client, err := createS3Client(
-		accessKey, secretKey, region, endpoint,
+	s3Client, err := c.getS3Client(
+		accessKey, secretKey, region, endpoint, usePathStyle,
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to create S3 client: %w", err)
@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	s3DefaultPartSize    = 5 * 1024 * 1024
+	s3MaxPartSize        = 5 * 1024 * 1024 * 1024
+	s3DefaultConcurrency = 5
+
+	// s3PartsPerStep is the number of parts uploaded before the part size is
+	// doubled, so streams of unknown size fit in 10000 parts.
+	s3PartsPerStep = 1000
+
+	// s3MaxGrowPartSize is the size up to which the part size is doubled.
+	// Every in-flight part is held in memory, so the peak memory of an upload
+	// is the part size × (concurrency + 1), with the defaults 384 MiB at most
+	// and streams up to ~450 GiB.
+	s3MaxGrowPartSize = 64 * 1024 * 1024
+
+	// S3MaxUploadMemoryMB is the maximum peak memory of an upload, in MiB. The
+	// part size × (concurrency + 1) of a destination can't exceed it and the
+	// part size is not grown beyond it.
+	S3MaxUploadMemoryMB = 2048
+)
+
+// s3MaxParts is the maximum number of parts of a multipart upload, it is a
+// variable so the tests can lower it.
+var s3MaxParts = 10000
+
+// S3UploadMemoryMB returns the peak memory, in MiB, of an upload with the
+// given part size (in MiB) and concurrency before the part size grows. A 0
+// part size or concurrency means the uploader default.
+func S3UploadMemoryMB(partSizeMB, concurrency int) int {
+	if partSizeMB <= 0 {
+		partSizeMB = s3DefaultPartSize / 1024 / 1024
+	}
+	if concurrency <= 0 {
+		concurrency = s3DefaultConcurrency
+	}
+
+	return partSizeMB * (concurrency + 1)
+}
+
+// UploadStats are the statistics of an upload.
+type UploadStats struct {
+	// Size is the number of uploaded bytes.
+	Size int64
+	// Parts is the number of parts of the S3 multipart upload. It is 0 for
+	// local files and for objects small enough to be uploaded at once.
+	Parts int
+	// Retries is the number of times a failed request was retried.
+	Retries int
+}
+
+// s3PartSize returns the size of the given part number (1 based). The size
+// grows from baseSize up to s3MaxGrowPartSize, as long as the peak memory
+// with the given concurrency stays under S3MaxUploadMemoryMB. A bigger
+// baseSize is never grown.
+func s3PartSize(baseSize int64, concurrency int, partNumber int) int64 {
+	memoryLimit := int64(S3MaxUploadMemoryMB) * 1024 * 1024 / int64(concurrency+1)
+	limit := min(
+		max(baseSize, s3MaxGrowPartSize),
+		max(baseSize, memoryLimit),
+		s3MaxPartSize,
+	)
+
+	size := baseSize
+	for i := s3PartsPerStep; i < partNumber && size < limit; i += s3PartsPerStep {
+		size *= 2
+	}
+	return min(size, limit)
+}
+
+// s3Retry runs fn until it succeeds, the context is canceled or the retry
+// window, measured from the first failure, is exceeded.
+func s3Retry(
+	ctx context.Context, window time.Duration, retries *atomic.Int64,
+	fn func() error,
+) error {
+	var firstFailure time.Time
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if firstFailure.IsZero() {
+			firstFailure = time.Now()
+		}
+		if ctx.Err() != nil || time.Since(firstFailure) >= window {
+			return err
+		}
+
+		backoff := 30 * time.Second
+		if attempt < 5 {
+			backoff = time.Second << attempt
+		}
+
+		retries.Add(1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// s3UploadAPI is the part of the S3 client used by the uploads.
+type s3UploadAPI interface {
+	PutObject(
+		ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error)
+	CreateMultipartUpload(
+		ctx context.Context, params *s3.CreateMultipartUploadInput,
+		optFns ...func(*s3.Options),
+	) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(
+		ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options),
+	) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(
+		ctx context.Context, params *s3.CompleteMultipartUploadInput,
+		optFns ...func(*s3.Options),
+	) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(
+		ctx context.Context, params *s3.AbortMultipartUploadInput,
+		optFns ...func(*s3.Options),
+	) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// s3MultipartUpload uploads the body of the input to S3 in parts. The parts
+// are kept in memory until they are uploaded, so a failed part is retried
+// within opts.RetryWindow without reading the body again. The peak memory is
+// the part size × (concurrency + 1).
+//
+// Objects smaller than one part are uploaded with a single request.
+func s3MultipartUpload(
+	ctx context.Context, s3Client s3UploadAPI, input *s3.PutObjectInput,
+	opts S3UploadOptions,
+) (UploadStats, error) {
+	baseSize := int64(s3DefaultPartSize)
+	if opts.PartSizeMB > 0 {
+		baseSize = int64(opts.PartSizeMB) * 1024 * 1024
+	}
+	concurrency := s3DefaultConcurrency
+	if opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	body := input.Body
+	retries := &atomic.Int64{}
+	stats := func(size int64, parts int) UploadStats {
+		return UploadStats{Size: size, Parts: parts, Retries: int(retries.Load())}
+	}
+
+	buf := make([]byte, s3PartSize(baseSize, concurrency, 1))
+	n, err := io.ReadFull(body, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return stats(0, 0), err
+	}
+
+	if err != nil {
+		err := s3Retry(ctx, opts.RetryWindow, retries, func() error {
+			input.Body = bytes.NewReader(buf[:n])
+			_, err := s3Client.PutObject(ctx, input)
+			return err
+		})
+		return stats(int64(n), 0), err
+	}
+
+	upload, err := s3Client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:                    input.Bucket,
+		Key:                       input.Key,
+		ContentType:               input.ContentType,
+		StorageClass:              input.StorageClass,
+		ServerSideEncryption:      input.ServerSideEncryption,
+		SSEKMSKeyId:               input.SSEKMSKeyId,
+		ObjectLockMode:            input.ObjectLockMode,
+		ObjectLockRetainUntilDate: input.ObjectLockRetainUntilDate,
+		ChecksumAlgorithm:         input.ChecksumAlgorithm,
+	})
+	if err != nil {
+		return stats(0, 0), err
+	}
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		parts    []types.CompletedPart
+		firstErr error
+		size     int64
+	)
+	sem := make(chan struct{}, concurrency)
+
+	uploadPart := func(partNumber int32, data []byte) {
+		defer wg.Done()
+		defer func() { <-sem }()
+
+		var out *s3.UploadPartOutput
+		err := s3Retry(uploadCtx, opts.RetryWindow, retries, func() error {
+			var err error
+			out, err = s3Client.UploadPart(uploadCtx, &s3.UploadPartInput{
+				Bucket:            input.Bucket,
+				Key:               input.Key,
+				UploadId:          upload.UploadId,
+				PartNumber:        aws.Int32(partNumber),
+				Body:              bytes.NewReader(data),
+				ChecksumAlgorithm: input.ChecksumAlgorithm,
+			})
+			return err
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error uploading part %d: %w", partNumber, err)
+				cancel()
+			}
+			return
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:          out.ETag,
+			PartNumber:    aws.Int32(partNumber),
+			ChecksumCRC32: out.ChecksumCRC32,
+		})
+	}
+
+	var readErr error
+	last := false
+	for partNumber := 1; ; partNumber++ {
+		if partNumber > s3MaxParts {
+			readErr = fmt.Errorf(
+				"the file exceeds the maximum of %d parts, increase the part size",
+				s3MaxParts,
+			)
+			break
+		}
+
+		sem <- struct{}{}
+		if uploadCtx.Err() != nil {
+			<-sem
+			break
+		}
+		size += int64(n)
+		wg.Add(1)
+		go uploadPart(int32(partNumber), buf[:n])
+
+		if last {
+			break
+		}
+
+		buf = make([]byte, s3PartSize(baseSize, concurrency, partNumber+1))
+		n, err = io.ReadFull(body, buf)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			readErr = err
+			break
+		}
+		last = err != nil
+	}
+
+	wg.Wait()
+
+	if readErr == nil {
+		readErr = firstErr
+	}
+	if readErr != nil {
+		_, _ = s3Client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   input.Bucket,
+			Key:      input.Key,
+			UploadId: upload.UploadId,
+		})
+		return stats(size, len(parts)), readErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return *parts[i].PartNumber < *parts[j].PartNumber
+	})
+
+	err = s3Retry(ctx, opts.RetryWindow, retries, func() error {
+		_, err := s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          input.Bucket,
+			Key:             input.Key,
+			UploadId:        upload.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+		return err
+	})
+	if err != nil {
+		return stats(size, len(parts)), fmt.Errorf("error completing upload: %w", err)
+	}
+
+	return stats(size, len(parts)), nil
+}
@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+const mib = 1024 * 1024
+
+func TestS3PartSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseSize    int64
+		concurrency int
+		partNumber  int
+		expected    int64
+	}{
+		{"First part", 5 * mib, 5, 1, 5 * mib},
+		{"Last part of the first step", 5 * mib, 5, 1000, 5 * mib},
+		{"Doubles after a step", 5 * mib, 5, 1001, 10 * mib},
+		{"Doubles every step", 5 * mib, 5, 3001, 40 * mib},
+		{"Stops growing at 64 MiB", 5 * mib, 5, 9000, 64 * mib},
+		{"Big parts are not grown", 100 * mib, 5, 9000, 100 * mib},
+		{"Growth is limited by the memory", 5 * mib, 63, 9000, 32 * mib},
+		{"Base size above the memory limit is kept", 100 * mib, 63, 9000, 100 * mib},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(
+				t, tt.expected, s3PartSize(tt.baseSize, tt.concurrency, tt.partNumber),
+			)
+		})
+	}
+}
+
+func TestS3UploadMemoryMB(t *testing.T) {
+	tests := []struct {
+		name        string
+		partSizeMB  int
+		concurrency int
+		expected    int
+	}{
+		{"Defaults", 0, 0, 30},
+		{"Default concurrency", 100, 0, 600},
+		{"Default part size", 0, 9, 50},
+		{"Custom", 1024, 1, 2048},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, S3UploadMemoryMB(tt.partSizeMB, tt.concurrency))
+		})
+	}
+}
+
+func TestS3Retry(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name            string
+		window          time.Duration
+		failures        int
+		cancel          bool
+		expectedErr     error
+		expectedCalls   int
+		expectedRetries int64
+	}{
+		{
+			name:          "Succeeds at once",
+			window:        time.Minute,
+			expectedCalls: 1,
+		},
+		{
+			name:            "Succeeds after a retry",
+			window:          time.Minute,
+			failures:        1,
+			expectedCalls:   2,
+			expectedRetries: 1,
+		},
+		{
+			name:          "No retries without a window",
+			failures:      5,
+			expectedErr:   errFailed,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Stops when the context is canceled",
+			window:        time.Minute,
+			failures:      5,
+			cancel:        true,
+			expectedErr:   errFailed,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			calls := 0
+			retries := &atomic.Int64{}
+			err := s3Retry(ctx, tt.window, retries, func() error {
+				calls++
+				if calls <= tt.failures {
+					return errFailed
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.Equal(t, tt.expectedRetries, retries.Load())
+		})
+	}
+}
+
+// fakeS3 is an in-memory s3UploadAPI.
+type fakeS3 struct {
+	mu sync.Mutex
+
+	// failPart makes the upload of the given part number fail failPartTimes
+	// times.
+	failPart      int32
+	failPartTimes int
+
+	object    []byte
+	parts     map[int32][]byte
+	completed bool
+	aborted   bool
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{parts: map[int32][]byte{}}
+}
+
+func (f *fakeS3) PutObject(
+	_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options),
+) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.object = data
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) CreateMultipartUpload(
+	_ context.Context, _ *s3.CreateMultipartUploadInput, _ ...func(*s3.Options),
+) (*s3.CreateMultipartUploadOutput, error) {
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (f *fakeS3) UploadPart(
+	_ context.Context, params *s3.UploadPartInput, _ ...func(*s3.Options),
+) (*s3.UploadPartOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if *params.PartNumber == f.failPart && f.failPartTimes != 0 {
+		f.failPartTimes--
+		return nil, errors.New("part failed")
+	}
+	f.parts[*params.PartNumber] = data
+	return &s3.UploadPartOutput{ETag: aws.String("etag")}, nil
+}
+
+func (f *fakeS3) CompleteMultipartUpload(
+	_ context.Context, params *s3.CompleteMultipartUploadInput,
+	_ ...func(*s3.Options),
+) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	numbers := []int32{}
+	for _, part := range params.MultipartUpload.Parts {
+		numbers = append(numbers, *part.PartNumber)
+	}
+	if !sort.SliceIsSorted(numbers, func(i, j int) bool {
+		return numbers[i] < numbers[j]
+	}) {
+		return nil, errors.New("parts are not sorted")
+	}
+
+	for _, number := range numbers {
+		f.object = append(f.object, f.parts[number]...)
+	}
+	f.completed = true
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3) AbortMultipartUpload(
+	_ context.Context, _ *s3.AbortMultipartUploadInput, _ ...func(*s3.Options),
+) (*s3.AbortMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func uploadInput(data []byte) *s3.PutObjectInput {
+	return &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("dump.zip"),
+		Body:   bytes.NewReader(data),
+	}
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestS3MultipartUpload(t *testing.T) {
+	t.Run("Small objects are uploaded at once", func(t *testing.T) {
+		client := newFakeS3()
+		data := testData(mib)
+
+		stats, err := s3MultipartUpload(
+			context.Background(), client, uploadInput(data), S3UploadOptions{},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, UploadStats{Size: mib, Parts: 0}, stats)
+		assert.Equal(t, data, client.object)
+		assert.False(t, client.completed)
+	})
+
+	t.Run("Big objects are uploaded in parts", func(t *testing.T) {
+		client := newFakeS3()
+		data := testData(12 * mib)
+
+		stats, err := s3MultipartUpload(
+			context.Background(), client, uploadInput(data), S3UploadOptions{},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, UploadStats{Size: 12 * mib, Parts: 3}, stats)
+		assert.True(t, client.completed)
+		assert.True(t, bytes.Equal(data, client.object))
+	})
+
+	t.Run("Failed parts are retried", func(t *testing.T) {
+		client := newFakeS3()
+		client.failPart = 2
+		client.failPartTimes = 1
+		data := testData(12 * mib)
+
+		stats, err := s3MultipartUpload(
+			context.Background(), client, uploadInput(data),
+			S3UploadOptions{RetryWindow: time.Minute},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, UploadStats{Size: 12 * mib, Parts: 3, Retries: 1}, stats)
+		assert.True(t, bytes.Equal(data, client.object))
+	})
+
+	t.Run("Failed uploads are aborted", func(t *testing.T) {
+		client := newFakeS3()
+		client.failPart = 2
+		client.failPartTimes = -1
+		data := testData(12 * mib)
+
+		_, err := s3MultipartUpload(
+			context.Background(), client, uploadInput(data), S3UploadOptions{},
+		)
+		assert.Error(t, err)
+		assert.True(t, client.aborted)
+		assert.False(t, client.completed)
+	})
+
+	t.Run("Uploads over the maximum parts are aborted", func(t *testing.T) {
+		maxParts := s3MaxParts
+		s3MaxParts = 2
+		defer func() { s3MaxParts = maxParts }()
+
+		client := newFakeS3()
+		data := testData(12 * mib)
+
+		_, err := s3MultipartUpload(
+			context.Background(), client, uploadInput(data), S3UploadOptions{},
+		)
+		assert.ErrorContains(t, err, "maximum of 2 parts")
+		assert.True(t, client.aborted)
+		assert.False(t, client.completed)
+	})
+}
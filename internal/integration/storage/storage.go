@@ -1,7 +1,41 @@
 package storage
 
-type Client struct{}
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3ClientsMax is the maximum number of cached S3 clients, when it is
+// reached the least recently used client is evicted.
+const s3ClientsMax = 64
+
+type Client struct {
+	// s3Clients caches the S3 clients by connection settings so the AWS
+	// config is loaded only once per destination.
+	s3Clients   map[string]*s3CachedClient
+	s3ClientsMu sync.Mutex
+}
+
+type s3CachedClient struct {
+	client   *s3.Client
+	lastUsed time.Time
+}
 
 func New() *Client {
-	return &Client{}
+	return &Client{
+		s3Clients: map[string]*s3CachedClient{},
+	}
+}
+
+// FileInfo is a file stored in a destination.
+type FileInfo struct {
+	// Path is the path of the file relative to the destination root, without
+	// a leading slash.
+	Path string
+	// Size is the size of the file, in bytes.
+	Size int64
+	// ModifiedAt is the last modification time of the file.
+	ModifiedAt time.Time
 }
@@ -1,25 +1,39 @@
 package backups
 
 import (
+	"sync"
+
 	"github.com/eduardolat/pgbackweb/internal/cron"
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/service/cluster"
 	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/google/uuid"
 )
 
 type Service struct {
 	dbgen             *dbgen.Queries
 	cr                *cron.Cron
 	executionsService *executions.Service
+	clusterService    *cluster.Service
+
+	// scheduled holds the schedule (time zone and cron expression) of the
+	// backups scheduled in this instance, used to sync the schedules changed
+	// by other instances.
+	scheduledMu sync.Mutex
+	scheduled   map[uuid.UUID]string
 }
 
 func New(
 	dbgen *dbgen.Queries,
 	cr *cron.Cron,
 	executionsService *executions.Service,
+	clusterService *cluster.Service,
 ) *Service {
 	return &Service{
 		dbgen:             dbgen,
 		cr:                cr,
 		executionsService: executionsService,
+		clusterService:    clusterService,
+		scheduled:         map[uuid.UUID]string{},
 	}
 }
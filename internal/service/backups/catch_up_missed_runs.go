@@ -0,0 +1,94 @@
+package backups
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/cronutil"
+)
+
+const (
+	CatchUpPolicySkip = "skip"
+	CatchUpPolicyOnce = "once"
+	CatchUpPolicyAll  = "all"
+
+	// maxCatchUpRuns is the maximum number of missed runs queued for a backup
+	// with the "all" catch up policy.
+	maxCatchUpRuns = 50
+)
+
+// CatchUpMissedRuns queues the runs of the active backups missed while the
+// application was down, according to the catch up policy of each backup.
+//
+// A run is missed if it was scheduled after the last execution of the backup,
+// skipped and deferred executions included.
+// Every missed run is claimed like a scheduled run, so it is queued only once
+// even if several instances start at the same time.
+func (s *Service) CatchUpMissedRuns() {
+	ctx := context.Background()
+	now := time.Now()
+
+	backups, err := s.dbgen.BackupsServiceGetCatchUpData(ctx)
+	if err != nil {
+		logger.Error("error getting backups to catch up", logger.KV{"error": err})
+		return
+	}
+
+	for _, backup := range backups {
+		loc, err := time.LoadLocation(backup.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		limit := maxCatchUpRuns
+		if backup.CatchUpPolicy == CatchUpPolicyOnce {
+			limit = 1
+		}
+
+		// Walking backwards from now bounds the work for backups that were
+		// not run in a long time
+		missed, err := cronutil.LastRunsBetween(
+			backup.CronExpression, loc, backup.LastRunAt, now, limit,
+		)
+		if err != nil {
+			logger.Error("error calculating missed runs", logger.KV{
+				"backup_id": backup.ID.String(),
+				"error":     err,
+			})
+			continue
+		}
+		if len(missed) == 0 {
+			continue
+		}
+
+		queued := 0
+		for _, scheduledAt := range missed {
+			claimed, err := s.clusterService.ClaimRun(
+				ctx, backup.ID.String(), scheduledAt.Round(time.Minute),
+			)
+			if err != nil {
+				logger.Error("error claiming missed run", logger.KV{
+					"backup_id": backup.ID.String(),
+					"error":     err,
+				})
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			if err := s.executionsService.RunScheduledExecution(ctx, backup.ID); err != nil {
+				continue
+			}
+			queued++
+		}
+
+		logger.Info("missed backup runs queued", logger.KV{
+			"backup_id": backup.ID.String(),
+			"policy":    backup.CatchUpPolicy,
+			"missed":    len(missed),
+			"queued":    queued,
+		})
+	}
+}
@@ -0,0 +1,21 @@
+-- name: BackupsServiceGetCatchUpData :many
+SELECT
+  backups.id,
+  backups.cron_expression,
+  backups.time_zone,
+  backups.catch_up_policy,
+  COALESCE(
+    (
+      SELECT MAX(COALESCE(
+        executions.queued_at, executions.started_at, executions.finished_at
+      ))
+      FROM executions
+      WHERE executions.backup_id = backups.id
+      AND executions.is_upload = false
+    ),
+    backups.created_at
+  )::TIMESTAMPTZ AS last_run_at
+FROM backups
+WHERE backups.is_active = true
+AND backups.catch_up_policy != 'skip'
+AND backups.run_after_backup_id IS NULL;
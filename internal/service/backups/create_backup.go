@@ -11,6 +11,8 @@ import (
 func (s *Service) CreateBackup(
 	ctx context.Context, params dbgen.BackupsServiceCreateBackupParams,
 ) (dbgen.Backup, error) {
+	// Chained backups keep a valid cron expression too, it is used if they
+	// stop running after another backup
 	if !validate.CronExpression(params.CronExpression) {
 		return dbgen.Backup{}, fmt.Errorf("invalid cron expression")
 	}
@@ -20,9 +22,5 @@ func (s *Service) CreateBackup(
 		return backup, err
 	}
 
-	if !backup.IsActive {
-		return backup, s.jobRemove(backup.ID)
-	}
-
-	return backup, s.jobUpsert(backup.ID, backup.TimeZone, backup.CronExpression)
+	return backup, s.jobSync(backup)
 }
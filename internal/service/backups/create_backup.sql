@@ -2,11 +2,15 @@
 INSERT INTO backups (
   database_id, destination_id, is_local, name, cron_expression, time_zone,
   is_active, dest_dir, retention_days, opt_data_only, opt_schema_only,
-  opt_clean, opt_if_exists, opt_create, opt_no_comments
+  opt_clean, opt_if_exists, opt_create, opt_no_comments, dump_rate_limit,
+  keep_daily, keep_weekly, keep_monthly, keep_yearly, keep_last,
+  catch_up_policy, run_after_backup_id, run_after_condition
 )
 VALUES (
   @database_id, @destination_id, @is_local, @name, @cron_expression, @time_zone,
   @is_active, @dest_dir, @retention_days, @opt_data_only, @opt_schema_only,
-  @opt_clean, @opt_if_exists, @opt_create, @opt_no_comments
+  @opt_clean, @opt_if_exists, @opt_create, @opt_no_comments, @dump_rate_limit,
+  @keep_daily, @keep_weekly, @keep_monthly, @keep_yearly, @keep_last,
+  @catch_up_policy, @run_after_backup_id, @run_after_condition
 )
 RETURNING *;
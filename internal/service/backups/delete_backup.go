@@ -2,14 +2,42 @@ package backups
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+// DeleteBackup deletes a backup and its executions. Backups with pinned
+// executions (or executions under legal hold) can't be deleted until they
+// are unpinned, and backups that others run after can't be deleted until
+// those are changed.
 func (s *Service) DeleteBackup(
 	ctx context.Context, id uuid.UUID,
 ) error {
-	err := s.jobRemove(id)
+	pinnedQty, err := s.dbgen.BackupsServiceGetPinnedExecutionsQty(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pinnedQty > 0 {
+		return fmt.Errorf(
+			"the backup has %d pinned executions, unpin them before deleting it",
+			pinnedQty,
+		)
+	}
+
+	chained, err := s.dbgen.BackupsServiceGetChainedBackupsNames(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(chained) > 0 {
+		return fmt.Errorf(
+			"the backups %s run after this backup, change their trigger before deleting it",
+			strings.Join(chained, ", "),
+		)
+	}
+
+	err = s.jobRemove(id)
 	if err != nil {
 		return err
 	}
@@ -1,3 +1,14 @@
 -- name: BackupsServiceDeleteBackup :exec
 DELETE FROM backups
 WHERE id = @id;
+
+-- name: BackupsServiceGetPinnedExecutionsQty :one
+SELECT COUNT(*) FROM executions
+WHERE backup_id = @backup_id
+AND pin_type IS NOT NULL
+AND status != 'deleted';
+
+-- name: BackupsServiceGetChainedBackupsNames :many
+SELECT name FROM backups
+WHERE run_after_backup_id = @backup_id
+ORDER BY name;
@@ -0,0 +1,133 @@
+package backups
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/util/cronutil"
+	"github.com/google/uuid"
+)
+
+// maxUpcomingRunsPerBackup is the maximum number of upcoming runs returned
+// for each backup, so backups running every minute don't flood the schedule.
+const maxUpcomingRunsPerBackup = 500
+
+// UpcomingRun is a scheduled run of a backup.
+type UpcomingRun struct {
+	BackupID     uuid.UUID `json:"backup_id"`
+	BackupName   string    `json:"backup_name"`
+	DatabaseName string    `json:"database_name"`
+	ScheduledAt  time.Time `json:"scheduled_at"`
+	// LastFileSize is the size in bytes of the last successful execution of the
+	// backup, 0 if unknown. It helps to spot heavy backups running together.
+	LastFileSize int64 `json:"last_file_size"`
+	// Overlaps is the number of runs of other backups scheduled at the same
+	// time.
+	Overlaps int `json:"overlaps"`
+}
+
+// InvalidSchedule is an active backup whose schedule can't be evaluated.
+type InvalidSchedule struct {
+	BackupID       uuid.UUID `json:"backup_id"`
+	BackupName     string    `json:"backup_name"`
+	CronExpression string    `json:"cron_expression"`
+	TimeZone       string    `json:"time_zone"`
+	Error          string    `json:"error"`
+}
+
+// UpcomingSchedule is the schedule of all active backups in a period.
+type UpcomingSchedule struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Runs    []UpcomingRun     `json:"runs"`
+	Invalid []InvalidSchedule `json:"invalid"`
+	// Truncated is true when a backup has more runs in the period than the
+	// ones returned.
+	Truncated bool `json:"truncated"`
+}
+
+// GetUpcomingRuns returns the runs of all active backups scheduled over the
+// next days, sorted by time.
+func (s *Service) GetUpcomingRuns(
+	ctx context.Context, days int,
+) (UpcomingSchedule, error) {
+	backups, err := s.dbgen.BackupsServiceGetUpcomingRunsData(ctx)
+	if err != nil {
+		return UpcomingSchedule{}, err
+	}
+
+	from := time.Now()
+	schedule := UpcomingSchedule{
+		From:    from,
+		To:      from.AddDate(0, 0, days),
+		Runs:    []UpcomingRun{},
+		Invalid: []InvalidSchedule{},
+	}
+
+	for _, backup := range backups {
+		invalid := InvalidSchedule{
+			BackupID:       backup.ID,
+			BackupName:     backup.Name,
+			CronExpression: backup.CronExpression,
+			TimeZone:       backup.TimeZone,
+		}
+
+		loc, err := time.LoadLocation(backup.TimeZone)
+		if err != nil {
+			invalid.Error = err.Error()
+			schedule.Invalid = append(schedule.Invalid, invalid)
+			continue
+		}
+
+		runs, err := cronutil.RunsBetween(
+			backup.CronExpression, loc, schedule.From, schedule.To,
+			maxUpcomingRunsPerBackup+1,
+		)
+		if err != nil {
+			invalid.Error = err.Error()
+			schedule.Invalid = append(schedule.Invalid, invalid)
+			continue
+		}
+
+		if len(runs) > maxUpcomingRunsPerBackup {
+			runs = runs[:maxUpcomingRunsPerBackup]
+			schedule.Truncated = true
+		}
+
+		for _, run := range runs {
+			schedule.Runs = append(schedule.Runs, UpcomingRun{
+				BackupID:     backup.ID,
+				BackupName:   backup.Name,
+				DatabaseName: backup.DatabaseName,
+				ScheduledAt:  run,
+				LastFileSize: backup.LastFileSize,
+			})
+		}
+	}
+
+	sort.SliceStable(schedule.Runs, func(i, j int) bool {
+		return schedule.Runs[i].ScheduledAt.Before(schedule.Runs[j].ScheduledAt)
+	})
+
+	// Runs are compared by minute, the precision of the cron expressions
+	runsByMinute := map[int64]int{}
+	for _, run := range schedule.Runs {
+		runsByMinute[run.ScheduledAt.Unix()/60]++
+	}
+	for i, run := range schedule.Runs {
+		schedule.Runs[i].Overlaps = runsByMinute[run.ScheduledAt.Unix()/60] - 1
+	}
+
+	return schedule, nil
+}
+
+// NextRuns returns the next n times at which a backup schedule is due.
+func NextRuns(cronExpression string, timeZone string, n int) ([]time.Time, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	return cronutil.NextRuns(cronExpression, loc, time.Now(), n)
+}
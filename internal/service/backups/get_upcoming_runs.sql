@@ -0,0 +1,22 @@
+-- name: BackupsServiceGetUpcomingRunsData :many
+SELECT
+  backups.id,
+  backups.name,
+  backups.cron_expression,
+  backups.time_zone,
+  databases.name AS database_name,
+  COALESCE((
+    SELECT executions.file_size
+    FROM executions
+    WHERE executions.backup_id = backups.id
+    AND executions.status = 'success'
+    AND executions.is_upload = false
+    AND executions.file_size IS NOT NULL
+    ORDER BY executions.started_at DESC
+    LIMIT 1
+  ), 0)::BIGINT AS last_file_size
+FROM backups
+INNER JOIN databases ON databases.id = backups.database_id
+WHERE backups.is_active = true
+AND backups.run_after_backup_id IS NULL
+ORDER BY backups.name;
@@ -3,5 +3,13 @@ package backups
 import "github.com/google/uuid"
 
 func (s *Service) jobRemove(backupID uuid.UUID) error {
-	return s.cr.RemoveJob(backupID)
+	if err := s.cr.RemoveJob(backupID); err != nil {
+		return err
+	}
+
+	s.scheduledMu.Lock()
+	delete(s.scheduled, backupID)
+	s.scheduledMu.Unlock()
+
+	return nil
 }
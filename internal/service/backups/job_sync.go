@@ -0,0 +1,23 @@
+package backups
+
+import "github.com/eduardolat/pgbackweb/internal/database/dbgen"
+
+const (
+	// RunAfterConditionSuccess runs a chained backup only when the backup it
+	// runs after succeeds.
+	RunAfterConditionSuccess = "success"
+	// RunAfterConditionAlways runs a chained backup when the backup it runs
+	// after finishes, whether it succeeds or fails.
+	RunAfterConditionAlways = "always"
+)
+
+// jobSync schedules the backup if it is active and triggered by its cron
+// expression, chained backups are run by the executions service when the
+// backup they run after finishes.
+func (s *Service) jobSync(backup dbgen.Backup) error {
+	if !backup.IsActive || backup.RunAfterBackupID.Valid {
+		return s.jobRemove(backup.ID)
+	}
+
+	return s.jobUpsert(backup.ID, backup.TimeZone, backup.CronExpression)
+}
@@ -9,8 +9,21 @@ import (
 func (s *Service) jobUpsert(
 	backupID uuid.UUID, timeZone string, cronExpression string,
 ) error {
-	return s.cr.UpsertJob(
+	err := s.cr.UpsertJob(
 		backupID, timeZone, cronExpression,
-		s.executionsService.RunExecution, context.Background(), backupID,
+		s.executionsService.RunScheduledExecution, context.Background(), backupID,
 	)
+	if err != nil {
+		return err
+	}
+
+	s.scheduledMu.Lock()
+	s.scheduled[backupID] = scheduleKey(timeZone, cronExpression)
+	s.scheduledMu.Unlock()
+
+	return nil
+}
+
+func scheduleKey(timeZone string, cronExpression string) string {
+	return timeZone + " " + cronExpression
 }
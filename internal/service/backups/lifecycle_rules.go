@@ -0,0 +1,31 @@
+package backups
+
+import (
+	"context"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+)
+
+func (s *Service) GetLifecycleRules(
+	ctx context.Context, backupID uuid.UUID,
+) ([]dbgen.BackupsServiceGetLifecycleRulesRow, error) {
+	return s.dbgen.BackupsServiceGetLifecycleRules(ctx, backupID)
+}
+
+func (s *Service) CreateLifecycleRule(
+	ctx context.Context, params dbgen.BackupsServiceCreateLifecycleRuleParams,
+) (dbgen.BackupLifecycleRule, error) {
+	return s.dbgen.BackupsServiceCreateLifecycleRule(ctx, params)
+}
+
+func (s *Service) DeleteLifecycleRule(
+	ctx context.Context, backupID, ruleID uuid.UUID,
+) error {
+	return s.dbgen.BackupsServiceDeleteLifecycleRule(
+		ctx, dbgen.BackupsServiceDeleteLifecycleRuleParams{
+			ID:       ruleID,
+			BackupID: backupID,
+		},
+	)
+}
@@ -0,0 +1,18 @@
+-- name: BackupsServiceGetLifecycleRules :many
+SELECT
+  backup_lifecycle_rules.*,
+  destinations.name AS destination_name,
+  destinations.is_local AS destination_is_local
+FROM backup_lifecycle_rules
+INNER JOIN destinations ON destinations.id = backup_lifecycle_rules.destination_id
+WHERE backup_lifecycle_rules.backup_id = @backup_id
+ORDER BY backup_lifecycle_rules.after_days ASC;
+
+-- name: BackupsServiceCreateLifecycleRule :one
+INSERT INTO backup_lifecycle_rules (backup_id, destination_id, after_days)
+VALUES (@backup_id, @destination_id, @after_days)
+RETURNING *;
+
+-- name: BackupsServiceDeleteLifecycleRule :exec
+DELETE FROM backup_lifecycle_rules
+WHERE id = @id AND backup_id = @backup_id;
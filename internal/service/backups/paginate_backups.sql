@@ -5,9 +5,12 @@ SELECT COUNT(*) FROM backups;
 SELECT
   backups.*,
   databases.name AS database_name,
-  destinations.name AS destination_name
+  destinations.name AS destination_name,
+  destinations.is_local AS destination_is_local,
+  run_after.name AS run_after_backup_name
 FROM backups
 INNER JOIN databases ON backups.database_id = databases.id
 LEFT JOIN destinations ON backups.destination_id = destinations.id
+LEFT JOIN backups AS run_after ON run_after.id = backups.run_after_backup_id
 ORDER BY backups.created_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
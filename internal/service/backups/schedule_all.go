@@ -4,25 +4,47 @@ import (
 	"context"
 
 	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/google/uuid"
 )
 
+// ScheduleAll syncs the scheduled jobs of this instance with the backups
+// stored in the database. Only the backups created, changed or deleted since
+// the last sync are rescheduled, so it can run periodically to pick up the
+// changes made through other instances.
 func (s *Service) ScheduleAll() {
-	activeBackups, err := s.dbgen.BackupsServiceGetScheduleAllData(
+	backups, err := s.dbgen.BackupsServiceGetScheduleAllData(
 		context.Background(),
 	)
 	if err != nil {
 		logger.Error("error getting all active backups", logger.KV{"error": err})
+		return
 	}
 
-	for _, backup := range activeBackups {
-		if !backup.IsActive {
+	s.scheduledMu.Lock()
+	scheduled := make(map[uuid.UUID]string, len(s.scheduled))
+	for id, key := range s.scheduled {
+		scheduled[id] = key
+	}
+	s.scheduledMu.Unlock()
+
+	changes := 0
+	for _, backup := range backups {
+		key, isScheduled := scheduled[backup.ID]
+		delete(scheduled, backup.ID)
+
+		// Chained backups are not scheduled, they run after another backup
+		shouldSchedule := backup.IsActive && !backup.RunAfterBackupID.Valid
+
+		if !shouldSchedule && isScheduled {
+			changes++
 			err := s.jobRemove(backup.ID)
 			if err != nil {
 				logger.Error("error removing inactive backup", logger.KV{"error": err})
 			}
 		}
 
-		if backup.IsActive {
+		if shouldSchedule && key != scheduleKey(backup.TimeZone, backup.CronExpression) {
+			changes++
 			err := s.jobUpsert(backup.ID, backup.TimeZone, backup.CronExpression)
 			if err != nil {
 				logger.Error("error scheduling backup", logger.KV{"error": err})
@@ -30,5 +52,15 @@ func (s *Service) ScheduleAll() {
 		}
 	}
 
-	logger.Info("all active backups scheduled")
+	// The remaining scheduled backups were deleted
+	for id := range scheduled {
+		changes++
+		if err := s.jobRemove(id); err != nil {
+			logger.Error("error removing deleted backup", logger.KV{"error": err})
+		}
+	}
+
+	if changes > 0 {
+		logger.Info("backups schedules synced", logger.KV{"changes": changes})
+	}
 }
@@ -3,6 +3,7 @@ SELECT
   id,
   is_active,
   cron_expression,
-  time_zone
+  time_zone,
+  run_after_backup_id
 FROM backups
 ORDER BY created_at DESC;
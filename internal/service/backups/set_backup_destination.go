@@ -0,0 +1,35 @@
+package backups
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+)
+
+// SetBackupDestination changes the destination where the new executions of
+// a backup are stored. The existing executions keep their own destination,
+// they are moved with the executions transfer.
+//
+// Legacy local backups can't be changed, their executions have no
+// destination of their own.
+func (s *Service) SetBackupDestination(
+	ctx context.Context, backupID, destinationID uuid.UUID,
+) (dbgen.Backup, error) {
+	backup, err := s.dbgen.BackupsServiceSetBackupDestination(
+		ctx, dbgen.BackupsServiceSetBackupDestinationParams{
+			BackupID:      backupID,
+			DestinationID: destinationID,
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return backup, fmt.Errorf(
+			"the destination of legacy local backups can't be changed",
+		)
+	}
+
+	return backup, err
+}
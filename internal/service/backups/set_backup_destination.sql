@@ -0,0 +1,6 @@
+-- name: BackupsServiceSetBackupDestination :one
+UPDATE backups
+SET destination_id = @destination_id
+WHERE id = @backup_id
+AND is_local = false
+RETURNING *;
@@ -12,9 +12,5 @@ func (s *Service) ToggleIsActive(ctx context.Context, backupID uuid.UUID) error
 		return err
 	}
 
-	if !backup.IsActive {
-		return s.jobRemove(backupID)
-	}
-
-	return s.jobUpsert(backupID, backup.TimeZone, backup.CronExpression)
+	return s.jobSync(backup)
 }
@@ -11,18 +11,34 @@ import (
 func (s *Service) UpdateBackup(
 	ctx context.Context, params dbgen.BackupsServiceUpdateBackupParams,
 ) (dbgen.Backup, error) {
+	// Chained backups keep a valid cron expression too, it is used if they
+	// stop running after another backup
 	if !validate.CronExpression(params.CronExpression.String) {
 		return dbgen.Backup{}, fmt.Errorf("invalid cron expression")
 	}
 
+	isChained := params.SetRunAfter && params.RunAfterBackupID.Valid
+	if isChained {
+		isInChain, err := s.dbgen.BackupsServiceIsInChain(
+			ctx, dbgen.BackupsServiceIsInChainParams{
+				ParentID: params.RunAfterBackupID.UUID,
+				BackupID: params.ID,
+			},
+		)
+		if err != nil {
+			return dbgen.Backup{}, err
+		}
+		if isInChain {
+			return dbgen.Backup{}, fmt.Errorf(
+				"a backup can't run after itself or after a backup that runs after it",
+			)
+		}
+	}
+
 	backup, err := s.dbgen.BackupsServiceUpdateBackup(ctx, params)
 	if err != nil {
 		return backup, err
 	}
 
-	if !backup.IsActive {
-		return backup, s.jobRemove(backup.ID)
-	}
-
-	return backup, s.jobUpsert(backup.ID, backup.TimeZone, backup.CronExpression)
+	return backup, s.jobSync(backup)
 }
@@ -12,6 +12,36 @@ SET
   opt_clean = COALESCE(sqlc.narg('opt_clean'), opt_clean),
   opt_if_exists = COALESCE(sqlc.narg('opt_if_exists'), opt_if_exists),
   opt_create = COALESCE(sqlc.narg('opt_create'), opt_create),
-  opt_no_comments = COALESCE(sqlc.narg('opt_no_comments'), opt_no_comments)
+  opt_no_comments = COALESCE(sqlc.narg('opt_no_comments'), opt_no_comments),
+  dump_rate_limit = COALESCE(sqlc.narg('dump_rate_limit'), dump_rate_limit),
+  keep_daily = COALESCE(sqlc.narg('keep_daily'), keep_daily),
+  keep_weekly = COALESCE(sqlc.narg('keep_weekly'), keep_weekly),
+  keep_monthly = COALESCE(sqlc.narg('keep_monthly'), keep_monthly),
+  keep_yearly = COALESCE(sqlc.narg('keep_yearly'), keep_yearly),
+  keep_last = COALESCE(sqlc.narg('keep_last'), keep_last),
+  catch_up_policy = COALESCE(sqlc.narg('catch_up_policy'), catch_up_policy),
+  run_after_backup_id = (
+    CASE WHEN @set_run_after::BOOLEAN
+    THEN sqlc.narg('run_after_backup_id')::UUID
+    ELSE run_after_backup_id
+    END
+  ),
+  run_after_condition = COALESCE(sqlc.narg('run_after_condition'), run_after_condition)
 WHERE id = @id
 RETURNING *;
+
+-- name: BackupsServiceIsInChain :one
+WITH RECURSIVE chain AS (
+  SELECT id, run_after_backup_id
+  FROM backups
+  WHERE id = @parent_id
+
+  UNION
+
+  SELECT backups.id, backups.run_after_backup_id
+  FROM backups
+  INNER JOIN chain ON backups.id = chain.run_after_backup_id
+)
+SELECT EXISTS (
+  SELECT 1 FROM chain WHERE id = @backup_id
+)::BOOLEAN;
@@ -0,0 +1,24 @@
+package blackouts
+
+import (
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+)
+
+const (
+	// ActionSkip skips the scheduled runs inside the window.
+	ActionSkip = "skip"
+	// ActionDefer defers the scheduled runs inside the window until it ends.
+	ActionDefer = "defer"
+)
+
+// Service manages the blackout windows, periods of time in which the
+// scheduled backups don't run, globally or for a database.
+type Service struct {
+	dbgen *dbgen.Queries
+}
+
+func New(dbgen *dbgen.Queries) *Service {
+	return &Service{
+		dbgen: dbgen,
+	}
+}
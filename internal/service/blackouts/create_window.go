@@ -0,0 +1,41 @@
+package blackouts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+)
+
+func (s *Service) CreateWindow(
+	ctx context.Context, params dbgen.BlackoutsServiceCreateWindowParams,
+) (dbgen.BlackoutWindow, error) {
+	if params.Action != ActionSkip && params.Action != ActionDefer {
+		return dbgen.BlackoutWindow{}, fmt.Errorf("invalid action %q", params.Action)
+	}
+
+	if params.CronExpression.Valid {
+		if !validate.CronExpression(params.CronExpression.String) {
+			return dbgen.BlackoutWindow{}, fmt.Errorf("invalid cron expression")
+		}
+		if _, err := time.LoadLocation(params.TimeZone.String); err != nil {
+			return dbgen.BlackoutWindow{}, fmt.Errorf("invalid time zone: %w", err)
+		}
+		if params.DurationMinutes.Int32 <= 0 {
+			return dbgen.BlackoutWindow{}, fmt.Errorf("duration must be greater than 0")
+		}
+	}
+
+	if !params.CronExpression.Valid {
+		if !params.StartsAt.Valid || !params.EndsAt.Valid {
+			return dbgen.BlackoutWindow{}, fmt.Errorf("start and end are required")
+		}
+		if !params.EndsAt.Time.After(params.StartsAt.Time) {
+			return dbgen.BlackoutWindow{}, fmt.Errorf("end must be after start")
+		}
+	}
+
+	return s.dbgen.BlackoutsServiceCreateWindow(ctx, params)
+}
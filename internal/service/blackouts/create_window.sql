@@ -0,0 +1,10 @@
+-- name: BlackoutsServiceCreateWindow :one
+INSERT INTO blackout_windows (
+  database_id, name, action, starts_at, ends_at,
+  cron_expression, time_zone, duration_minutes
+)
+VALUES (
+  @database_id, @name, @action, @starts_at, @ends_at,
+  @cron_expression, @time_zone, @duration_minutes
+)
+RETURNING *;
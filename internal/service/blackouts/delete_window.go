@@ -0,0 +1,11 @@
+package blackouts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) DeleteWindow(ctx context.Context, id uuid.UUID) error {
+	return s.dbgen.BlackoutsServiceDeleteWindow(ctx, id)
+}
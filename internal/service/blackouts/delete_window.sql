@@ -0,0 +1,2 @@
+-- name: BlackoutsServiceDeleteWindow :exec
+DELETE FROM blackout_windows WHERE id = @id;
@@ -0,0 +1,129 @@
+package blackouts
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/blackoututil"
+	"github.com/google/uuid"
+)
+
+// ActiveWindow is a blackout window active at a given time.
+type ActiveWindow struct {
+	ID uuid.UUID
+	// DatabaseID is empty for global windows
+	DatabaseID uuid.NullUUID
+	Name       string
+	Action     string
+	// EndsAt is the end of the active occurrence of the window
+	EndsAt time.Time
+}
+
+// GetActiveWindows returns the blackout windows active at the given time.
+// Windows that can't be evaluated are logged and ignored.
+func (s *Service) GetActiveWindows(
+	ctx context.Context, t time.Time,
+) ([]ActiveWindow, error) {
+	windows, err := s.dbgen.BlackoutsServiceGetAllWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active := []ActiveWindow{}
+	for _, window := range windows {
+		isActive, endsAt, err := toWindow(window).ActiveAt(t)
+		if err != nil {
+			logger.Error("error evaluating blackout window", logger.KV{
+				"window_id": window.ID.String(),
+				"error":     err,
+			})
+			continue
+		}
+		if !isActive {
+			continue
+		}
+
+		active = append(active, ActiveWindow{
+			ID:         window.ID,
+			DatabaseID: window.DatabaseID,
+			Name:       window.Name,
+			Action:     window.Action,
+			EndsAt:     endsAt,
+		})
+	}
+
+	return active, nil
+}
+
+// GetBackupActiveWindow returns the blackout window that applies to the
+// backup at the given time, see SelectWindow.
+func (s *Service) GetBackupActiveWindow(
+	ctx context.Context, backupID uuid.UUID, t time.Time,
+) (ActiveWindow, bool, error) {
+	databaseID, err := s.dbgen.BlackoutsServiceGetBackupDatabaseID(ctx, backupID)
+	if err != nil {
+		return ActiveWindow{}, false, err
+	}
+
+	windows, err := s.GetActiveWindows(ctx, t)
+	if err != nil {
+		return ActiveWindow{}, false, err
+	}
+
+	window, found := SelectWindow(windows, databaseID)
+	return window, found, nil
+}
+
+// SelectWindow returns the active window that applies to a database, out of
+// the global windows and the windows of the database. Skip windows take
+// precedence over defer windows, and among windows with the same action the
+// one that ends last is returned.
+func SelectWindow(
+	windows []ActiveWindow, databaseID uuid.UUID,
+) (ActiveWindow, bool) {
+	var selected ActiveWindow
+	found := false
+
+	for _, window := range windows {
+		if window.DatabaseID.Valid && window.DatabaseID.UUID != databaseID {
+			continue
+		}
+
+		switch {
+		case !found:
+		case window.Action != selected.Action:
+			if window.Action != ActionSkip {
+				continue
+			}
+		case !window.EndsAt.After(selected.EndsAt):
+			continue
+		}
+
+		selected = window
+		found = true
+	}
+
+	return selected, found
+}
+
+func toWindow(window dbgen.BlackoutsServiceGetAllWindowsRow) blackoututil.Window {
+	if !window.CronExpression.Valid {
+		return blackoututil.Window{
+			StartsAt: window.StartsAt.Time,
+			EndsAt:   window.EndsAt.Time,
+		}
+	}
+
+	loc, err := time.LoadLocation(window.TimeZone.String)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return blackoututil.Window{
+		CronExpression: window.CronExpression.String,
+		Location:       loc,
+		Duration:       time.Duration(window.DurationMinutes.Int32) * time.Minute,
+	}
+}
@@ -0,0 +1,2 @@
+-- name: BlackoutsServiceGetBackupDatabaseID :one
+SELECT database_id FROM backups WHERE id = @backup_id;
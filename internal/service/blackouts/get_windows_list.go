@@ -0,0 +1,13 @@
+package blackouts
+
+import (
+	"context"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+)
+
+func (s *Service) GetAllWindows(
+	ctx context.Context,
+) ([]dbgen.BlackoutsServiceGetAllWindowsRow, error) {
+	return s.dbgen.BlackoutsServiceGetAllWindows(ctx)
+}
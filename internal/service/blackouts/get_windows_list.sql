@@ -0,0 +1,7 @@
+-- name: BlackoutsServiceGetAllWindows :many
+SELECT
+  blackout_windows.*,
+  databases.name AS database_name
+FROM blackout_windows
+LEFT JOIN databases ON databases.id = blackout_windows.database_id
+ORDER BY blackout_windows.created_at DESC;
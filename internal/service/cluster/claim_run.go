@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+)
+
+// ClaimRun claims the run of a scheduled job for this node. It returns false
+// if another node already claimed the same run.
+func (s *Service) ClaimRun(
+	ctx context.Context, key string, scheduledAt time.Time,
+) (bool, error) {
+	claimed, err := s.dbgen.ClusterServiceClaimRun(
+		ctx, dbgen.ClusterServiceClaimRunParams{
+			JobKey:      key,
+			ScheduledAt: scheduledAt,
+			NodeID:      s.nodeID,
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return claimed > 0, nil
+}
+
+// DeleteOldRunClaims deletes the claims of the runs older than 7 days.
+func (s *Service) DeleteOldRunClaims() {
+	err := s.dbgen.ClusterServiceDeleteOldRunClaims(context.Background())
+	if err != nil {
+		logger.Error("error deleting old run claims", logger.KV{"error": err})
+		return
+	}
+
+	logger.Info("old run claims deleted")
+}
@@ -0,0 +1,8 @@
+-- name: ClusterServiceClaimRun :execrows
+INSERT INTO cron_run_claims (job_key, scheduled_at, node_id)
+VALUES (@job_key, @scheduled_at, @node_id)
+ON CONFLICT (job_key, scheduled_at) DO NOTHING;
+
+-- name: ClusterServiceDeleteOldRunClaims :exec
+DELETE FROM cron_run_claims
+WHERE claimed_at < NOW() - INTERVAL '7 days';
@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+)
+
+// ClaimStartupRun claims the startup run of a job for this node. It returns
+// false if any node already claimed it within the given window, so nodes
+// that boot around the same time run it only once.
+//
+// The claim is serialized with an advisory lock on the job key, so nodes
+// claiming at the same time see the claims of each other.
+func (s *Service) ClaimStartupRun(
+	ctx context.Context, key string, window time.Duration,
+) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	qtx := s.dbgen.WithTx(tx)
+
+	if err := qtx.ClusterServiceLockStartupRun(ctx, key); err != nil {
+		return false, err
+	}
+
+	claimed, err := qtx.ClusterServiceClaimStartupRun(
+		ctx, dbgen.ClusterServiceClaimStartupRunParams{
+			JobKey:        key,
+			NodeID:        s.nodeID,
+			WindowSeconds: int32(window.Seconds()),
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return claimed > 0, nil
+}
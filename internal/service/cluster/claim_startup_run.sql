@@ -0,0 +1,11 @@
+-- name: ClusterServiceLockStartupRun :exec
+SELECT pg_advisory_xact_lock(hashtext(@job_key));
+
+-- name: ClusterServiceClaimStartupRun :execrows
+INSERT INTO cron_run_claims (job_key, scheduled_at, node_id)
+SELECT @job_key, NOW(), @node_id
+WHERE NOT EXISTS (
+  SELECT 1 FROM cron_run_claims
+  WHERE job_key = @job_key
+  AND claimed_at > NOW() - make_interval(secs => @window_seconds::INTEGER)
+);
@@ -0,0 +1,53 @@
+package cluster
+
+import (
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/config"
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+)
+
+// HeartbeatInterval is how often every node sends its heartbeat, scheduled
+// with the equivalent HeartbeatCron expression.
+const (
+	HeartbeatInterval = time.Minute
+	HeartbeatCron     = "* * * * *"
+)
+
+// Service coordinates multiple instances (nodes) of the application that
+// share the same metadata database.
+type Service struct {
+	env    config.Env
+	db     *sql.DB
+	dbgen  *dbgen.Queries
+	nodeID string
+}
+
+func New(env config.Env, db *sql.DB, dbgen *dbgen.Queries) *Service {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+
+	return &Service{
+		env:    env,
+		db:     db,
+		dbgen:  dbgen,
+		nodeID: hostname + "-" + uuid.NewString()[:8],
+	}
+}
+
+// NodeID returns the identifier of this instance of the application.
+func (s *Service) NodeID() string {
+	return s.nodeID
+}
+
+// NodeTimeout returns how long a node can go without sending a heartbeat
+// before it is considered gone, a multiple of the heartbeat interval set by
+// PBW_NODE_TIMEOUT_HEARTBEATS.
+func (s *Service) NodeTimeout() time.Duration {
+	return HeartbeatInterval * time.Duration(s.env.PBW_NODE_TIMEOUT_HEARTBEATS)
+}
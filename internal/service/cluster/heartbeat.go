@@ -0,0 +1,26 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/eduardolat/pgbackweb/internal/logger"
+)
+
+// Heartbeat records that this node is alive. A node that does not send a
+// heartbeat for NodeTimeout is considered gone, and the executions and
+// restorations it was running are reconciled as interrupted.
+func (s *Service) Heartbeat() {
+	ctx := context.Background()
+
+	if err := s.dbgen.ClusterServiceHeartbeat(ctx, s.nodeID); err != nil {
+		logger.Error("error sending node heartbeat", logger.KV{
+			"node_id": s.nodeID,
+			"error":   err,
+		})
+		return
+	}
+
+	if err := s.dbgen.ClusterServiceDeleteStaleNodes(ctx); err != nil {
+		logger.Error("error deleting stale nodes", logger.KV{"error": err})
+	}
+}
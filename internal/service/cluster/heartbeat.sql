@@ -0,0 +1,8 @@
+-- name: ClusterServiceHeartbeat :exec
+INSERT INTO cluster_nodes (node_id)
+VALUES (@node_id)
+ON CONFLICT (node_id) DO UPDATE SET heartbeat_at = NOW();
+
+-- name: ClusterServiceDeleteStaleNodes :exec
+DELETE FROM cluster_nodes
+WHERE heartbeat_at < NOW() - INTERVAL '7 days';
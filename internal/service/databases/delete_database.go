@@ -2,12 +2,42 @@ package databases
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+// DeleteDatabase deletes a database and, in cascade, its backups and their
+// executions. Databases with pinned executions (or executions under legal
+// hold) can't be deleted until they are unpinned, and neither can databases
+// whose backups are run after by backups of other databases.
 func (s *Service) DeleteDatabase(
 	ctx context.Context, id uuid.UUID,
 ) error {
+	pinnedQty, err := s.dbgen.DatabasesServiceGetPinnedExecutionsQty(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pinnedQty > 0 {
+		return fmt.Errorf(
+			"the database has %d pinned executions, unpin them before deleting it",
+			pinnedQty,
+		)
+	}
+
+	chained, err := s.dbgen.DatabasesServiceGetExternalChainedBackupsNames(
+		ctx, id,
+	)
+	if err != nil {
+		return err
+	}
+	if len(chained) > 0 {
+		return fmt.Errorf(
+			"the backups %s run after backups of this database, change their trigger before deleting it",
+			strings.Join(chained, ", "),
+		)
+	}
+
 	return s.dbgen.DatabasesServiceDeleteDatabase(ctx, id)
 }
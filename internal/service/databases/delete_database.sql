@@ -1,3 +1,18 @@
 -- name: DatabasesServiceDeleteDatabase :exec
 DELETE FROM databases
 WHERE id = @id;
+
+-- name: DatabasesServiceGetPinnedExecutionsQty :one
+SELECT COUNT(*) FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+WHERE backups.database_id = @database_id
+AND executions.pin_type IS NOT NULL
+AND executions.status != 'deleted';
+
+-- name: DatabasesServiceGetExternalChainedBackupsNames :many
+SELECT children.name
+FROM backups AS children
+INNER JOIN backups AS parents ON parents.id = children.run_after_backup_id
+WHERE parents.database_id = @database_id
+AND children.database_id != @database_id
+ORDER BY children.name;
@@ -0,0 +1,252 @@
+package destinations
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
+	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
+	"github.com/google/uuid"
+)
+
+// AuditDestinationResult is the result of comparing the files stored in a
+// destination against the executions that reference them.
+type AuditDestinationResult struct {
+	// TotalFiles is the number of files found under the backups directories.
+	TotalFiles int
+	// TotalBytes is the size of all the files found, in bytes.
+	TotalBytes int64
+	// UntrackedFiles are the backup files not referenced by any execution.
+	UntrackedFiles []storage.FileInfo
+	// UntrackedBytes is the size of the untracked files, in bytes.
+	UntrackedBytes int64
+	// MissingFiles are the successful executions whose file doesn't exist.
+	MissingFiles []dbgen.DestinationsServiceGetAuditExecutionsRow
+	// CleanupError is set when the untracked files can't be cleaned up.
+	CleanupError string
+}
+
+// AuditDestination lists the files stored in the audit directories of the
+// destination (see auditDirs) and compares them against the executions.
+//
+// Only dump files that PG Back Web can import and restore are reported as
+// untracked, so unrelated files sharing the same directories are never
+// touched.
+func (s *Service) AuditDestination(
+	ctx context.Context, destinationID uuid.UUID,
+) (AuditDestinationResult, error) {
+	dest, err := s.GetDestination(ctx, destinationID)
+	if err != nil {
+		return AuditDestinationResult{}, err
+	}
+
+	nullDestinationID := uuid.NullUUID{UUID: destinationID, Valid: true}
+
+	backups, err := s.dbgen.DestinationsServiceGetAuditBackups(
+		ctx, nullDestinationID,
+	)
+	if err != nil {
+		return AuditDestinationResult{}, err
+	}
+
+	destExecutions, err := s.dbgen.DestinationsServiceGetAuditExecutions(
+		ctx, nullDestinationID,
+	)
+	if err != nil {
+		return AuditDestinationResult{}, err
+	}
+
+	tracked, err := s.getTrackedFiles(ctx)
+	if err != nil {
+		return AuditDestinationResult{}, err
+	}
+
+	result := AuditDestinationResult{
+		UntrackedFiles: []storage.FileInfo{},
+		MissingFiles:   []dbgen.DestinationsServiceGetAuditExecutionsRow{},
+	}
+
+	dirs, err := auditDirs(backups, destExecutions)
+	if err != nil {
+		result.CleanupError = err.Error()
+	}
+
+	files := map[string]storage.FileInfo{}
+	for _, dir := range dirs {
+		list, err := s.listDestinationFiles(dest, dir)
+		if err != nil {
+			return AuditDestinationResult{}, err
+		}
+		for _, file := range list {
+			files[file.Path] = file
+		}
+	}
+
+	for _, file := range files {
+		result.TotalFiles++
+		result.TotalBytes += file.Size
+
+		if tracked[s.fileLocation(dest, file.Path)] || !isBackupFile(file.Path) {
+			continue
+		}
+		result.UntrackedFiles = append(result.UntrackedFiles, file)
+		result.UntrackedBytes += file.Size
+	}
+	sort.Slice(result.UntrackedFiles, func(i, j int) bool {
+		return result.UntrackedFiles[i].Path < result.UntrackedFiles[j].Path
+	})
+
+	for _, execution := range destExecutions {
+		if execution.Status != "success" {
+			continue
+		}
+		filePath := strutil.CreatePath(false, execution.Path.String)
+		if _, ok := files[filePath]; ok || !isInDirs(filePath, dirs) {
+			continue
+		}
+		result.MissingFiles = append(result.MissingFiles, execution)
+	}
+
+	return result, nil
+}
+
+// isBackupFile returns true if the file is a dump that PG Back Web creates,
+// imports or restores.
+func isBackupFile(filePath string) bool {
+	return executions.IsImportableFile(filePath)
+}
+
+// auditDirs returns the directories audited in a destination: the directory
+// of every backup using it or with executions in it, and the directories of
+// the executions stored outside of them, like files imported from another
+// directory or created before the backup directory was changed.
+//
+// A backup storing its files in the root of the destination is skipped,
+// listing the root would include unrelated files, and an error is returned
+// along with the other directories.
+func auditDirs(
+	backups []dbgen.DestinationsServiceGetAuditBackupsRow,
+	destExecutions []dbgen.DestinationsServiceGetAuditExecutionsRow,
+) ([]string, error) {
+	var err error
+	dirs := []string{}
+
+	for _, backup := range backups {
+		dir := strutil.RemoveTrailingSlash(strutil.CreatePath(false, backup.DestDir))
+		if dir == "" {
+			err = fmt.Errorf(
+				"the backup %q stores its files in the root of the destination", backup.Name,
+			)
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	for _, execution := range destExecutions {
+		filePath := strutil.CreatePath(false, execution.Path.String)
+		dir := path.Dir(filePath)
+		if dir == "." || isInDirs(filePath, dirs) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	return dirs, err
+}
+
+// isInDirs returns true if the file is stored under any of the directories.
+func isInDirs(filePath string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(filePath, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// fileLocation returns a key that identifies where a file is physically
+// stored, so destinations sharing the same bucket or local path resolve to
+// the same key.
+func (s *Service) fileLocation(
+	dest dbgen.DestinationsServiceGetDestinationRow, filePath string,
+) string {
+	return s.storageLocation(
+		dest.IsLocal, dest.LocalPath.String, dest.Endpoint, dest.BucketName,
+		filePath,
+	)
+}
+
+func (s *Service) storageLocation(
+	isLocal bool, localPath, endpoint, bucketName, filePath string,
+) string {
+	if isLocal {
+		return "local:" + s.ints.StorageClient.LocalGetFullPath(localPath, filePath)
+	}
+
+	return fmt.Sprintf(
+		"s3:%s/%s/%s", endpoint, bucketName, strutil.CreatePath(false, filePath),
+	)
+}
+
+// getTrackedFiles returns the locations of the files referenced by any
+// execution that is not deleted, of any backup and destination.
+func (s *Service) getTrackedFiles(ctx context.Context) (map[string]bool, error) {
+	files, err := s.dbgen.DestinationsServiceGetTrackedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := map[string]bool{}
+	for _, file := range files {
+		// Legacy local backups are stored in the default local directory
+		if file.BackupIsLocal {
+			tracked[s.storageLocation(true, "", "", "", file.Path.String)] = true
+			continue
+		}
+		if !file.DestinationIsLocal.Valid {
+			continue
+		}
+
+		tracked[s.storageLocation(
+			file.DestinationIsLocal.Bool, file.DestinationLocalPath.String,
+			file.DestinationEndpoint.String, file.DestinationBucketName.String,
+			file.Path.String,
+		)] = true
+	}
+
+	return tracked, nil
+}
+
+// listDestinationFiles lists the files stored in a destination under the
+// given directory.
+func (s *Service) listDestinationFiles(
+	dest dbgen.DestinationsServiceGetDestinationRow, dir string,
+) ([]storage.FileInfo, error) {
+	if dest.IsLocal {
+		return s.ints.StorageClient.LocalListFiles(dest.LocalPath.String, dir)
+	}
+
+	return s.ints.StorageClient.S3ListFiles(
+		dest.DecryptedAccessKey, dest.DecryptedSecretKey, dest.Region,
+		dest.Endpoint, dest.BucketName, dir, dest.UsePathStyle,
+	)
+}
+
+// deleteDestinationFile deletes a file stored in a destination.
+func (s *Service) deleteDestinationFile(
+	dest dbgen.DestinationsServiceGetDestinationRow, filePath string,
+) error {
+	if dest.IsLocal {
+		return s.ints.StorageClient.LocalDelete(dest.LocalPath.String, filePath)
+	}
+
+	return s.ints.StorageClient.S3Delete(
+		dest.DecryptedAccessKey, dest.DecryptedSecretKey, dest.Region,
+		dest.Endpoint, dest.BucketName, filePath, dest.UsePathStyle,
+	)
+}
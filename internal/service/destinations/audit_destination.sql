@@ -0,0 +1,44 @@
+-- name: DestinationsServiceGetAuditBackups :many
+SELECT backups.id, backups.name, backups.dest_dir
+FROM backups
+WHERE backups.destination_id = @destination_id
+OR EXISTS (
+  SELECT 1 FROM executions
+  WHERE executions.backup_id = backups.id
+  AND executions.destination_id = @destination_id
+  AND executions.status != 'deleted'
+);
+
+-- name: DestinationsServiceGetAuditExecutions :many
+SELECT
+  executions.id,
+  executions.backup_id,
+  executions.status,
+  executions.path,
+  executions.file_size,
+  executions.started_at,
+  backups.name AS backup_name
+FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+WHERE COALESCE(
+  executions.destination_id, backups.destination_id
+) = @destination_id
+AND executions.path IS NOT NULL
+AND executions.status != 'deleted'
+ORDER BY executions.started_at DESC;
+
+-- name: DestinationsServiceGetTrackedFiles :many
+SELECT
+  executions.path,
+  backups.is_local AS backup_is_local,
+  destinations.is_local AS destination_is_local,
+  destinations.local_path AS destination_local_path,
+  destinations.endpoint AS destination_endpoint,
+  destinations.bucket_name AS destination_bucket_name
+FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
+WHERE executions.path IS NOT NULL
+AND executions.status != 'deleted';
@@ -0,0 +1,84 @@
+package destinations
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
+	"github.com/google/uuid"
+)
+
+// CleanupDestinationOrphans deletes the given untracked backup files, they
+// must be the files reviewed by the user in the audit.
+//
+// A file is skipped if it is not a backup file under the audit directories
+// of the destination, or if any execution of any destination sharing the
+// same storage references it.
+//
+// Returns the number of files deleted and skipped.
+func (s *Service) CleanupDestinationOrphans(
+	ctx context.Context, destinationID uuid.UUID, paths []string,
+) (int, int, error) {
+	dest, err := s.GetDestination(ctx, destinationID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	backups, err := s.dbgen.DestinationsServiceGetAuditBackups(
+		ctx, uuid.NullUUID{UUID: destinationID, Valid: true},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	destExecutions, err := s.dbgen.DestinationsServiceGetAuditExecutions(
+		ctx, uuid.NullUUID{UUID: destinationID, Valid: true},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dirs, err := auditDirs(backups, destExecutions)
+	if err != nil {
+		return 0, 0, errors.New(
+			"a backup stores its files in the root of the destination, the cleanup is disabled to avoid deleting unrelated files",
+		)
+	}
+
+	tracked, err := s.getTrackedFiles(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deleted, skipped := 0, 0
+	for _, filePath := range paths {
+		filePath = strutil.CreatePath(false, filePath)
+		if !isBackupFile(filePath) || !isInDirs(filePath, dirs) ||
+			strings.Contains(filePath, "..") ||
+			tracked[s.fileLocation(dest, filePath)] {
+			skipped++
+			continue
+		}
+
+		if err := s.deleteDestinationFile(dest, filePath); err != nil {
+			logger.Error("error deleting orphaned file", logger.KV{
+				"destination_id": destinationID.String(),
+				"path":           filePath,
+				"error":          err.Error(),
+			})
+			skipped++
+			continue
+		}
+
+		deleted++
+	}
+
+	logger.Info("orphaned files deleted", logger.KV{
+		"destination_id": destinationID.String(),
+		"deleted":        deleted,
+		"skipped":        skipped,
+	})
+	return deleted, skipped, nil
+}
@@ -2,19 +2,35 @@ package destinations
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
 )
 
 func (s *Service) CreateDestination(
 	ctx context.Context, params dbgen.DestinationsServiceCreateDestinationParams,
 ) (dbgen.Destination, error) {
-	err := s.TestDestination(
-		params.AccessKey, params.SecretKey, params.Region, params.Endpoint,
-		params.BucketName,
-	)
-	if err != nil {
-		return dbgen.Destination{}, err
+	if params.IsLocal {
+		_, err := s.TestLocalDestination(params.LocalPath.String)
+		if err != nil {
+			return dbgen.Destination{}, err
+		}
+	}
+
+	if !params.IsLocal {
+		err := validateUploadMemory(params.UploadPartSize, params.UploadConcurrency)
+		if err != nil {
+			return dbgen.Destination{}, err
+		}
+
+		err = s.TestDestination(
+			params.AccessKey, params.SecretKey, params.Region, params.Endpoint,
+			params.BucketName, params.UsePathStyle, params.ObjectLockMode.String != "",
+		)
+		if err != nil {
+			return dbgen.Destination{}, err
+		}
 	}
 
 	params.EncryptionKey = s.env.PBW_ENCRYPTION_KEY
@@ -24,3 +40,17 @@ func (s *Service) CreateDestination(
 
 	return dest, err
 }
+
+// validateUploadMemory checks that the peak memory of the uploads to a
+// destination, the part size × (concurrency + 1), is within the limit.
+func validateUploadMemory(partSizeMB, concurrency int32) error {
+	memory := storage.S3UploadMemoryMB(int(partSizeMB), int(concurrency))
+	if memory > storage.S3MaxUploadMemoryMB {
+		return fmt.Errorf(
+			"the part size × (concurrency + 1) is %d MiB, it can't exceed %d MiB",
+			memory, storage.S3MaxUploadMemoryMB,
+		)
+	}
+
+	return nil
+}
@@ -1,11 +1,20 @@
 -- name: DestinationsServiceCreateDestination :one
 INSERT INTO destinations (
   name, bucket_name, region, endpoint,
-  access_key, secret_key
+  access_key, secret_key, is_local, local_path,
+  use_path_style, storage_class, sse, sse_kms_key_id,
+  object_lock_mode, object_lock_days,
+  upload_rate_limit, upload_part_size, upload_concurrency,
+  low_space_threshold, max_running_executions
 )
 VALUES (
   @name, @bucket_name, @region, @endpoint,
   pgp_sym_encrypt(@access_key, @encryption_key),
-  pgp_sym_encrypt(@secret_key, @encryption_key)
+  pgp_sym_encrypt(@secret_key, @encryption_key),
+  @is_local, @local_path,
+  @use_path_style, @storage_class, @sse, @sse_kms_key_id,
+  @object_lock_mode, @object_lock_days,
+  @upload_rate_limit, @upload_part_size, @upload_concurrency,
+  @low_space_threshold, @max_running_executions
 )
 RETURNING *;
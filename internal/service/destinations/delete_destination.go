@@ -2,12 +2,71 @@ package destinations
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+// DeleteDestination deletes a destination and, in cascade, its backups and
+// their executions. Destinations with pinned executions (or executions under
+// legal hold) can't be deleted until they are unpinned, and neither can
+// destinations whose backups are run after by backups of other destinations.
+//
+// Executions transferred between destinations must be settled first: the
+// executions moved away from a backup of this destination would be deleted
+// with it, and the ones moved here from backups of other destinations would
+// be left without a file.
 func (s *Service) DeleteDestination(
 	ctx context.Context, id uuid.UUID,
 ) error {
-	return s.dbgen.DestinationsServiceDeleteDestination(ctx, id)
+	dest, err := s.GetDestination(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	pinnedQty, err := s.dbgen.DestinationsServiceGetPinnedExecutionsQty(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pinnedQty > 0 {
+		return fmt.Errorf(
+			"the destination has %d pinned executions, unpin them before deleting it",
+			pinnedQty,
+		)
+	}
+
+	chained, err := s.dbgen.DestinationsServiceGetExternalChainedBackupsNames(
+		ctx, id,
+	)
+	if err != nil {
+		return err
+	}
+	if len(chained) > 0 {
+		return fmt.Errorf(
+			"the backups %s run after backups of this destination, change their trigger before deleting it",
+			strings.Join(chained, ", "),
+		)
+	}
+
+	strandedQty, err := s.dbgen.DestinationsServiceGetStrandedExecutionsQty(
+		ctx, id,
+	)
+	if err != nil {
+		return err
+	}
+	if strandedQty > 0 {
+		return fmt.Errorf(
+			"%d executions were transferred to or from this destination, move the backups to their new destination or transfer the executions back before deleting it",
+			strandedQty,
+		)
+	}
+
+	err = s.dbgen.DestinationsServiceDeleteDestination(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.forgetS3Client(dest)
+	return nil
 }
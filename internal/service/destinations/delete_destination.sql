@@ -1,3 +1,37 @@
 -- name: DestinationsServiceDeleteDestination :exec
 DELETE FROM destinations
 WHERE id = @id;
+
+-- name: DestinationsServiceGetPinnedExecutionsQty :one
+SELECT COUNT(*) FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+WHERE (
+  executions.destination_id = @destination_id
+  OR backups.destination_id = @destination_id
+)
+AND executions.pin_type IS NOT NULL
+AND executions.status != 'deleted';
+
+-- name: DestinationsServiceGetExternalChainedBackupsNames :many
+SELECT children.name
+FROM backups AS children
+INNER JOIN backups AS parents ON parents.id = children.run_after_backup_id
+WHERE parents.destination_id = @destination_id
+AND children.destination_id IS DISTINCT FROM @destination_id
+ORDER BY children.name;
+
+-- name: DestinationsServiceGetStrandedExecutionsQty :one
+SELECT COUNT(*) FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+WHERE executions.status != 'deleted'
+AND executions.path IS NOT NULL
+AND (
+  (
+    backups.destination_id = @destination_id
+    AND executions.destination_id IS DISTINCT FROM @destination_id
+  )
+  OR (
+    executions.destination_id = @destination_id
+    AND backups.destination_id IS DISTINCT FROM @destination_id
+  )
+);
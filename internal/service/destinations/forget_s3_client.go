@@ -0,0 +1,18 @@
+package destinations
+
+import (
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+)
+
+// forgetS3Client removes the cached S3 client of a destination so the next
+// storage call uses the updated connection settings.
+func (s *Service) forgetS3Client(dest dbgen.DestinationsServiceGetDestinationRow) {
+	if dest.IsLocal {
+		return
+	}
+
+	s.ints.StorageClient.S3ForgetClient(
+		dest.DecryptedAccessKey, dest.DecryptedSecretKey, dest.Region,
+		dest.Endpoint, dest.UsePathStyle,
+	)
+}
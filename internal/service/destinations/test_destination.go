@@ -6,12 +6,14 @@ import (
 	"fmt"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
 	"github.com/google/uuid"
 )
 
 func (s *Service) TestDestinationAndStoreResult(
 	ctx context.Context, destinationID uuid.UUID,
 ) error {
+	var freeSpace sql.NullInt64
 	storeRes := func(ok bool, err error) error {
 		var errMsg string
 		if err != nil {
@@ -23,6 +25,7 @@ func (s *Service) TestDestinationAndStoreResult(
 				DestinationID: destinationID,
 				TestOk:        sql.NullBool{Valid: true, Bool: ok},
 				TestError:     sql.NullString{Valid: true, String: errMsg},
+				FreeSpace:     freeSpace,
 			},
 		)
 		if secondErr != nil {
@@ -36,10 +39,21 @@ func (s *Service) TestDestinationAndStoreResult(
 		return storeRes(false, fmt.Errorf("error getting destination: %w", err))
 	}
 
-	err = s.TestDestination(
-		dest.DecryptedAccessKey, dest.DecryptedSecretKey, dest.Region,
-		dest.Endpoint, dest.BucketName,
-	)
+	if dest.IsLocal {
+		var freeBytes int64
+		freeBytes, err = s.TestLocalDestination(dest.LocalPath.String)
+		if err == nil {
+			freeSpace = sql.NullInt64{Valid: true, Int64: freeBytes}
+			s.checkLowSpace(dest, freeBytes)
+		}
+	}
+	if !dest.IsLocal {
+		err = s.TestDestination(
+			dest.DecryptedAccessKey, dest.DecryptedSecretKey, dest.Region,
+			dest.Endpoint, dest.BucketName, dest.UsePathStyle,
+			dest.ObjectLockMode.Valid,
+		)
+	}
 	if err != nil && dest.TestOk.Valid && dest.TestOk.Bool {
 		s.webhooksService.RunDestinationUnhealthy(dest.ID)
 	}
@@ -53,13 +67,65 @@ func (s *Service) TestDestinationAndStoreResult(
 	return storeRes(true, nil)
 }
 
+// checkLowSpace runs the storage_low_space webhooks when the free space of a
+// local destination drops below its threshold. They only run when the
+// threshold is crossed, not on every test.
+func (s *Service) checkLowSpace(
+	dest dbgen.DestinationsServiceGetDestinationRow, freeBytes int64,
+) {
+	if dest.LowSpaceThreshold <= 0 {
+		return
+	}
+
+	threshold := int64(dest.LowSpaceThreshold) * 1024 * 1024 * 1024
+	wasLow := dest.FreeSpace.Valid && dest.FreeSpace.Int64 < threshold
+	if freeBytes < threshold && !wasLow {
+		logger.Info("destination is running low on space", logger.KV{
+			"destination_id": dest.ID.String(),
+			"free_space":     freeBytes,
+		})
+		s.webhooksService.RunStorageLowSpace(dest.ID)
+	}
+}
+
+// TestDestination tests the connection to a S3 destination. If objectLock is
+// true it also checks that the bucket has object lock enabled.
 func (s *Service) TestDestination(
 	accessKey, secretKey, region, endpoint, bucketName string,
+	usePathStyle, objectLock bool,
 ) error {
-	err := s.ints.StorageClient.S3Test(accessKey, secretKey, region, endpoint, bucketName)
+	err := s.ints.StorageClient.S3Test(
+		accessKey, secretKey, region, endpoint, bucketName, usePathStyle,
+	)
 	if err != nil {
 		return fmt.Errorf("error testing destination: %w", err)
 	}
 
+	if objectLock {
+		err = s.ints.StorageClient.S3TestObjectLock(
+			accessKey, secretKey, region, endpoint, bucketName, usePathStyle,
+		)
+		if err != nil {
+			return fmt.Errorf("error testing destination: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// TestLocalDestination tests that the local path of a destination exists and
+// is writable.
+//
+// Returns the free space available in the path, in bytes.
+func (s *Service) TestLocalDestination(localPath string) (int64, error) {
+	if localPath == "" {
+		return 0, fmt.Errorf("error testing destination: local path is required")
+	}
+
+	freeBytes, err := s.ints.StorageClient.LocalTest(localPath)
+	if err != nil {
+		return 0, fmt.Errorf("error testing destination: %w", err)
+	}
+
+	return freeBytes, nil
+}
@@ -2,5 +2,6 @@
 UPDATE destinations
 SET test_ok = @test_ok,
     test_error = @test_error,
+    free_space = sqlc.narg('free_space'),
     last_test_at = NOW()
 WHERE id = @destination_id;
@@ -9,16 +9,35 @@ import (
 func (s *Service) UpdateDestination(
 	ctx context.Context, params dbgen.DestinationsServiceUpdateDestinationParams,
 ) (dbgen.Destination, error) {
-	err := s.TestDestination(
-		params.AccessKey.String, params.SecretKey.String, params.Region.String,
-		params.Endpoint.String, params.BucketName.String,
-	)
+	current, err := s.GetDestination(ctx, params.ID)
+	if err != nil {
+		return dbgen.Destination{}, err
+	}
+
+	if current.IsLocal {
+		_, err = s.TestLocalDestination(params.LocalPath.String)
+	}
+	if !current.IsLocal {
+		err = validateUploadMemory(
+			params.UploadPartSize.Int32, params.UploadConcurrency.Int32,
+		)
+	}
+	if !current.IsLocal && err == nil {
+		err = s.TestDestination(
+			params.AccessKey.String, params.SecretKey.String, params.Region.String,
+			params.Endpoint.String, params.BucketName.String,
+			params.UsePathStyle.Bool, params.ObjectLockMode.String != "",
+		)
+	}
 	if err != nil {
 		return dbgen.Destination{}, err
 	}
 
 	params.EncryptionKey = s.env.PBW_ENCRYPTION_KEY
 	dest, err := s.dbgen.DestinationsServiceUpdateDestination(ctx, params)
+	if err == nil {
+		s.forgetS3Client(current)
+	}
 
 	_ = s.TestDestinationAndStoreResult(ctx, dest.ID)
 
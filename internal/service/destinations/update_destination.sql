@@ -5,6 +5,18 @@ SET
   bucket_name = COALESCE(sqlc.narg('bucket_name'), bucket_name),
   region = COALESCE(sqlc.narg('region'), region),
   endpoint = COALESCE(sqlc.narg('endpoint'), endpoint),
+  local_path = COALESCE(sqlc.narg('local_path'), local_path),
+  use_path_style = COALESCE(sqlc.narg('use_path_style')::BOOLEAN, use_path_style),
+  storage_class = NULLIF(COALESCE(sqlc.narg('storage_class')::TEXT, storage_class), ''),
+  sse = NULLIF(COALESCE(sqlc.narg('sse')::TEXT, sse), ''),
+  sse_kms_key_id = NULLIF(COALESCE(sqlc.narg('sse_kms_key_id')::TEXT, sse_kms_key_id), ''),
+  object_lock_mode = NULLIF(COALESCE(sqlc.narg('object_lock_mode')::TEXT, object_lock_mode), ''),
+  object_lock_days = NULLIF(COALESCE(sqlc.narg('object_lock_days')::INTEGER, object_lock_days), 0),
+  upload_rate_limit = COALESCE(sqlc.narg('upload_rate_limit')::INTEGER, upload_rate_limit),
+  upload_part_size = COALESCE(sqlc.narg('upload_part_size')::INTEGER, upload_part_size),
+  upload_concurrency = COALESCE(sqlc.narg('upload_concurrency')::INTEGER, upload_concurrency),
+  low_space_threshold = COALESCE(sqlc.narg('low_space_threshold')::INTEGER, low_space_threshold),
+  max_running_executions = COALESCE(sqlc.narg('max_running_executions')::INTEGER, max_running_executions),
   access_key = CASE
     WHEN sqlc.narg('access_key')::TEXT IS NOT NULL
     THEN pgp_sym_encrypt(sqlc.narg('access_key')::TEXT, sqlc.arg('encryption_key')::TEXT)
@@ -0,0 +1,71 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+)
+
+// ApplyLifecycleRules moves the executions that match a lifecycle rule of
+// their backup to the destination of that rule. When several rules of the
+// same backup match, the one with the most days wins.
+//
+// Failed transfers are recorded in the execution and retried with an
+// exponential backoff. If a previous run is still moving files this run is
+// skipped.
+func (s *Service) ApplyLifecycleRules() {
+	if !s.lifecycleMu.TryLock() {
+		logger.Info("lifecycle rules are already being applied, skipping")
+		return
+	}
+	defer s.lifecycleMu.Unlock()
+
+	ctx := context.Background()
+
+	transfers, err := s.dbgen.ExecutionsServiceGetLifecycleTransfers(ctx)
+	if err != nil {
+		logger.Error(
+			"error applying lifecycle rules",
+			logger.KV{"error": err},
+		)
+		return
+	}
+
+	moved := 0
+	for _, transfer := range transfers {
+		err := s.TransferExecution(
+			ctx, transfer.ExecutionID, transfer.DestinationID,
+		)
+		// The pending transfers are left for the next run
+		if errors.Is(err, errShuttingDown) {
+			break
+		}
+		if err != nil {
+			logger.Error("error applying lifecycle rule", logger.KV{
+				"execution_id":   transfer.ExecutionID.String(),
+				"destination_id": transfer.DestinationID.String(),
+				"error":          err,
+			})
+
+			err = s.dbgen.ExecutionsServiceSetLifecycleFailure(
+				ctx, dbgen.ExecutionsServiceSetLifecycleFailureParams{
+					ID:    transfer.ExecutionID,
+					Error: sql.NullString{Valid: true, String: err.Error()},
+				},
+			)
+			if err != nil {
+				logger.Error("error storing lifecycle rule failure", logger.KV{
+					"execution_id": transfer.ExecutionID.String(),
+					"error":        err,
+				})
+			}
+			continue
+		}
+		moved++
+	}
+
+	logger.Info("lifecycle rules applied", logger.KV{"moved": moved})
+}
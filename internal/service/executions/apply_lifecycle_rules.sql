@@ -0,0 +1,46 @@
+-- name: ExecutionsServiceGetLifecycleTransfers :many
+-- For every successful execution returns the destination of the lifecycle
+-- rule with the most days that applies to it, only if the execution is not
+-- already stored there.
+SELECT transfers.execution_id, transfers.destination_id
+FROM (
+  SELECT DISTINCT ON (executions.id)
+    executions.id AS execution_id,
+    backup_lifecycle_rules.destination_id AS destination_id,
+    (
+      CASE WHEN executions.destination_id IS NULL AND backups.is_local
+      THEN NULL
+      ELSE COALESCE(executions.destination_id, backups.destination_id)
+      END
+    ) AS current_destination_id
+  FROM executions
+  INNER JOIN backups ON backups.id = executions.backup_id
+  INNER JOIN backup_lifecycle_rules
+    ON backup_lifecycle_rules.backup_id = backups.id
+  WHERE executions.status = 'success'
+  AND executions.path IS NOT NULL
+  AND executions.finished_at IS NOT NULL
+  AND (
+    executions.lifecycle_retry_at IS NULL
+    OR executions.lifecycle_retry_at <= NOW()
+  )
+  AND (
+    executions.finished_at +
+    (backup_lifecycle_rules.after_days || ' days')::INTERVAL
+  ) < NOW()
+  ORDER BY executions.id, backup_lifecycle_rules.after_days DESC
+) AS transfers
+WHERE transfers.current_destination_id IS DISTINCT FROM transfers.destination_id;
+
+-- name: ExecutionsServiceSetLifecycleFailure :exec
+-- Records a failed lifecycle transfer, the next attempt is delayed
+-- exponentially from 10 minutes up to 24 hours.
+UPDATE executions
+SET
+  lifecycle_attempts = lifecycle_attempts + 1,
+  lifecycle_error = @error,
+  lifecycle_retry_at = NOW() + LEAST(
+    INTERVAL '10 minutes' * POWER(2, LEAST(lifecycle_attempts, 8)),
+    INTERVAL '24 hours'
+  )
+WHERE id = @id;
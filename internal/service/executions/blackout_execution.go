@@ -0,0 +1,75 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/google/uuid"
+)
+
+// DeferExecution queues a new execution of a backup that doesn't start until
+// the given time, it is used for the runs scheduled inside a blackout window.
+//
+// A backup has at most one deferred execution, the runs scheduled while it
+// is waiting are collapsed into it.
+func (s *Service) DeferExecution(
+	ctx context.Context, backupID uuid.UUID, until time.Time, message string,
+) error {
+	ex, err := s.dbgen.ExecutionsServiceDeferExecution(
+		ctx, dbgen.ExecutionsServiceDeferExecutionParams{
+			BackupID:      backupID,
+			Message:       sql.NullString{Valid: true, String: message},
+			DeferredUntil: sql.NullTime{Valid: true, Time: until},
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Info("backup already deferred", logger.KV{
+			"backup_id": backupID.String(),
+		})
+		return nil
+	}
+	if err != nil {
+		logger.Error("error deferring backup", logger.KV{
+			"backup_id": backupID.String(),
+			"error":     err.Error(),
+		})
+		return err
+	}
+
+	logger.Info("backup deferred", logger.KV{
+		"backup_id":      backupID.String(),
+		"execution_id":   ex.ID.String(),
+		"deferred_until": until.String(),
+	})
+	return nil
+}
+
+// SkipExecution records a skipped execution of a backup in its history, it
+// is used for the runs scheduled inside a blackout window.
+func (s *Service) SkipExecution(
+	ctx context.Context, backupID uuid.UUID, message string,
+) error {
+	ex, err := s.dbgen.ExecutionsServiceSkipExecution(
+		ctx, dbgen.ExecutionsServiceSkipExecutionParams{
+			BackupID: backupID,
+			Message:  sql.NullString{Valid: true, String: message},
+		},
+	)
+	if err != nil {
+		logger.Error("error skipping backup", logger.KV{
+			"backup_id": backupID.String(),
+			"error":     err.Error(),
+		})
+		return err
+	}
+
+	logger.Info("backup skipped", logger.KV{
+		"backup_id":    backupID.String(),
+		"execution_id": ex.ID.String(),
+	})
+	return nil
+}
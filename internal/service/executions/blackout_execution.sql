@@ -0,0 +1,21 @@
+-- name: ExecutionsServiceDeferExecution :one
+INSERT INTO executions (
+  backup_id, destination_id, status, message, queued_at, deferred_until
+)
+SELECT id, destination_id, 'queued', @message, NOW(), @deferred_until
+FROM backups
+WHERE id = @backup_id
+AND NOT EXISTS (
+  SELECT 1 FROM executions
+  WHERE executions.backup_id = @backup_id
+  AND executions.status = 'queued'
+  AND executions.deferred_until IS NOT NULL
+)
+RETURNING *;
+
+-- name: ExecutionsServiceSkipExecution :one
+INSERT INTO executions (backup_id, destination_id, status, message, finished_at)
+SELECT id, destination_id, 'skipped', @message, NOW()
+FROM backups
+WHERE id = @backup_id
+RETURNING *;
@@ -1,4 +1,6 @@
 -- name: ExecutionsServiceCreateExecution :one
-INSERT INTO executions (backup_id, status, message, path)
-VALUES (@backup_id, @status, @message, @path)
+INSERT INTO executions (backup_id, destination_id, status, message, path)
+SELECT id, destination_id, @status, @message, @path
+FROM backups
+WHERE id = @backup_id
 RETURNING *;
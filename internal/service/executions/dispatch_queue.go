@@ -0,0 +1,141 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/queueutil"
+)
+
+// DispatchQueue starts the queued executions that fit in the concurrency
+// limits (PBW_MAX_RUNNING_EXECUTIONS, PBW_MAX_RUNNING_EXECUTIONS_PER_SERVER
+// and the limit of each destination), oldest first.
+//
+// The queue is stored in the database, so every instance of the application
+// dispatches from the same queue. The queue state is read and the executions
+// are claimed under a transaction level advisory lock, so the limits hold
+// across instances.
+func (s *Service) DispatchQueue() {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	ctx := context.Background()
+
+	claimed, err := s.claimQueuedExecutions(ctx)
+	if err != nil {
+		logger.Error("error dispatching executions queue", logger.KV{
+			"error": err,
+		})
+		return
+	}
+
+	for _, execution := range claimed {
+		go func() {
+			defer s.running.Done()
+			_ = s.runQueuedExecution(
+				context.Background(), execution.ID, execution.BackupID,
+			)
+			s.DispatchQueue()
+		}()
+	}
+}
+
+// claimQueuedExecutions claims the queued executions that fit in the
+// concurrency limits. Every claimed execution is started in the running
+// tracker, and the caller must call Done when it finishes.
+func (s *Service) claimQueuedExecutions(
+	ctx context.Context,
+) ([]dbgen.ExecutionsServiceGetQueueStateRow, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	qtx := s.dbgen.WithTx(tx)
+
+	if err := qtx.ExecutionsServiceLockQueue(ctx); err != nil {
+		return nil, err
+	}
+
+	state, err := qtx.ExecutionsServiceGetQueueState(
+		ctx, s.env.PBW_ENCRYPTION_KEY,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	running := []queueutil.Item{}
+	queued := []dbgen.ExecutionsServiceGetQueueStateRow{}
+	queuedItems := []queueutil.Item{}
+	for _, row := range state {
+		item := queueutil.Item{
+			Server:           queueutil.ServerKey(row.DecryptedDatabaseConnectionString),
+			Destination:      row.DestinationID.String(),
+			DestinationLimit: int(row.DestinationMaxRunningExecutions),
+		}
+
+		if row.Status == "running" {
+			running = append(running, item)
+			continue
+		}
+		queued = append(queued, row)
+		queuedItems = append(queuedItems, item)
+	}
+
+	picked := queueutil.Pick(running, queuedItems, queueutil.Limits{
+		Global:    s.env.PBW_MAX_RUNNING_EXECUTIONS,
+		PerServer: s.env.PBW_MAX_RUNNING_EXECUTIONS_PER_SERVER,
+	})
+
+	claimed := []dbgen.ExecutionsServiceGetQueueStateRow{}
+	done := func() {
+		for range claimed {
+			s.running.Done()
+		}
+	}
+
+	for _, i := range picked {
+		execution := queued[i]
+
+		// The application is shutting down, the queued executions are left for
+		// other instances or the next start
+		if !s.running.Start() {
+			break
+		}
+
+		rows, err := qtx.ExecutionsServiceClaimQueuedExecution(
+			ctx, dbgen.ExecutionsServiceClaimQueuedExecutionParams{
+				ID:     execution.ID,
+				NodeID: sql.NullString{Valid: true, String: s.clusterService.NodeID()},
+			},
+		)
+		if err != nil {
+			s.running.Done()
+			done()
+			return nil, err
+		}
+
+		// Deleted since the queue state was read
+		if rows == 0 {
+			s.running.Done()
+			continue
+		}
+
+		claimed = append(claimed, execution)
+	}
+
+	if err := tx.Commit(); err != nil {
+		done()
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// DrainExecutions stops starting queued executions and transfers in this
+// instance and waits until the running ones finish or the context is done.
+func (s *Service) DrainExecutions(ctx context.Context) error {
+	return s.running.Drain(ctx)
+}
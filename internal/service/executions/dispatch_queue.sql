@@ -0,0 +1,42 @@
+-- name: ExecutionsServiceQueueExecution :one
+INSERT INTO executions (backup_id, destination_id, status, queued_at)
+SELECT id, destination_id, 'queued', NOW()
+FROM backups
+WHERE id = @backup_id
+RETURNING *;
+
+-- name: ExecutionsServiceGetQueueState :many
+SELECT
+  executions.id,
+  executions.backup_id,
+  executions.status,
+  COALESCE(
+    executions.destination_id, backups.destination_id
+  )::UUID AS destination_id,
+  COALESCE(destinations.max_running_executions, 0)::INTEGER AS destination_max_running_executions,
+  pgp_sym_decrypt(
+    databases.connection_string, sqlc.arg('encryption_key')::TEXT
+  ) AS decrypted_database_connection_string
+FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+INNER JOIN databases ON databases.id = backups.database_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
+WHERE executions.status IN ('queued', 'running')
+AND (
+  executions.deferred_until IS NULL OR executions.deferred_until <= NOW()
+)
+ORDER BY executions.queued_at ASC NULLS FIRST, executions.started_at ASC;
+
+-- name: ExecutionsServiceClaimQueuedExecution :execrows
+UPDATE executions
+SET
+  status = 'running',
+  started_at = NOW(),
+  node_id = @node_id
+WHERE id = @id
+AND status = 'queued';
+
+-- name: ExecutionsServiceLockQueue :exec
+SELECT pg_advisory_xact_lock(hashtext('pgbackweb:executions-queue'));
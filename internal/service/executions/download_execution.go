@@ -0,0 +1,81 @@
+package executions
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
+	"github.com/google/uuid"
+)
+
+// ExecutionDownload describes how the file of an execution should be sent
+// to the client. Exactly one of LocalPath, Link or Object is set.
+type ExecutionDownload struct {
+	// FileName is the name of the file that the client should save.
+	FileName string
+	// LocalPath is the full path of the file when it is stored locally.
+	LocalPath string
+	// Link is a presigned S3 URL, only set in the "presigned" download mode.
+	Link string
+	// Object is the S3 object to stream to the client, only set in the
+	// "proxy" download mode. Its body must be closed by the caller.
+	Object *storage.S3Object
+}
+
+// DownloadExecution prepares the download of the file associated with the
+// given execution according to the PBW_DOWNLOAD_MODE configuration.
+//
+// byteRange is an optional HTTP Range header value (e.g. "bytes=0-99") that
+// is forwarded to S3 when the file is streamed through the server.
+func (s *Service) DownloadExecution(
+	ctx context.Context, executionID uuid.UUID, byteRange string,
+) (ExecutionDownload, error) {
+	data, err := s.dbgen.ExecutionsServiceGetDownloadLinkOrPathData(
+		ctx, dbgen.ExecutionsServiceGetDownloadLinkOrPathDataParams{
+			ExecutionID:   executionID,
+			DecryptionKey: s.env.PBW_ENCRYPTION_KEY,
+		},
+	)
+	if err != nil {
+		return ExecutionDownload{}, err
+	}
+
+	if !data.Path.Valid {
+		return ExecutionDownload{}, fmt.Errorf("execution has no file associated")
+	}
+
+	download := ExecutionDownload{FileName: path.Base(data.Path.String)}
+
+	if data.IsLocal || data.DestinationIsLocal.Bool {
+		download.LocalPath = s.ints.StorageClient.LocalGetFullPath(
+			data.LocalPath.String, data.Path.String,
+		)
+		return download, nil
+	}
+
+	if s.env.PBW_DOWNLOAD_MODE == "presigned" {
+		download.Link, err = s.ints.StorageClient.S3GetDownloadLink(
+			data.DecryptedAccessKey, data.DecryptedSecretKey, data.Region.String,
+			data.Endpoint.String, data.BucketName.String, data.Path.String,
+			data.UsePathStyle.Bool, s.env.PBW_PRESIGNED_URL_EXPIRATION,
+		)
+		if err != nil {
+			return ExecutionDownload{}, err
+		}
+		return download, nil
+	}
+
+	obj, err := s.ints.StorageClient.S3Download(
+		data.DecryptedAccessKey, data.DecryptedSecretKey, data.Region.String,
+		data.Endpoint.String, data.BucketName.String, data.Path.String,
+		data.UsePathStyle.Bool, byteRange,
+	)
+	if err != nil {
+		return ExecutionDownload{}, err
+	}
+	download.Object = &obj
+
+	return download, nil
+}
@@ -1,27 +1,46 @@
 package executions
 
 import (
+	"database/sql"
+	"sync"
+
 	"github.com/eduardolat/pgbackweb/internal/config"
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/integration"
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
+	"github.com/eduardolat/pgbackweb/internal/service/cluster"
 	"github.com/eduardolat/pgbackweb/internal/service/webhooks"
+	"github.com/eduardolat/pgbackweb/internal/util/drainutil"
 )
 
 type Service struct {
-	env             config.Env
-	dbgen           *dbgen.Queries
-	ints            *integration.Integration
-	webhooksService *webhooks.Service
+	env              config.Env
+	db               *sql.DB
+	dbgen            *dbgen.Queries
+	ints             *integration.Integration
+	webhooksService  *webhooks.Service
+	clusterService   *cluster.Service
+	blackoutsService *blackouts.Service
+
+	lifecycleMu sync.Mutex
+	queueMu     sync.Mutex
+
+	// running tracks the executions running in this instance
+	running drainutil.Tracker
 }
 
 func New(
-	env config.Env, dbgen *dbgen.Queries, ints *integration.Integration,
-	webhooksService *webhooks.Service,
+	env config.Env, db *sql.DB, dbgen *dbgen.Queries, ints *integration.Integration,
+	webhooksService *webhooks.Service, clusterService *cluster.Service,
+	blackoutsService *blackouts.Service,
 ) *Service {
 	return &Service{
-		env:             env,
-		dbgen:           dbgen,
-		ints:            ints,
-		webhooksService: webhooksService,
+		env:              env,
+		db:               db,
+		dbgen:            dbgen,
+		ints:             ints,
+		webhooksService:  webhooksService,
+		clusterService:   clusterService,
+		blackoutsService: blackoutsService,
 	}
 }
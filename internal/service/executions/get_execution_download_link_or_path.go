@@ -9,6 +9,11 @@ import (
 	"github.com/google/uuid"
 )
 
+// restorationLinkExpiration is the expiration of the links used to restore
+// the executions, it doesn't depend on PBW_PRESIGNED_URL_EXPIRATION so the
+// link of a slow restoration doesn't expire before it is downloaded.
+const restorationLinkExpiration = 12 * time.Hour
+
 // GetExecutionDownloadLinkOrPath returns a download link for the file associated
 // with the given execution. If the execution is stored locally, the link will
 // be a file path.
@@ -32,13 +37,16 @@ func (s *Service) GetExecutionDownloadLinkOrPath(
 		return false, "", fmt.Errorf("execution has no file associated")
 	}
 
-	if data.IsLocal {
-		return true, s.ints.StorageClient.LocalGetFullPath(data.Path.String), nil
+	if data.IsLocal || data.DestinationIsLocal.Bool {
+		return true, s.ints.StorageClient.LocalGetFullPath(
+			data.LocalPath.String, data.Path.String,
+		), nil
 	}
 
 	link, err := s.ints.StorageClient.S3GetDownloadLink(
 		data.DecryptedAccessKey, data.DecryptedSecretKey, data.Region.String,
-		data.Endpoint.String, data.BucketName.String, data.Path.String, time.Hour*12,
+		data.Endpoint.String, data.BucketName.String, data.Path.String,
+		data.UsePathStyle.Bool, restorationLinkExpiration,
 	)
 	if err != nil {
 		return false, "", err
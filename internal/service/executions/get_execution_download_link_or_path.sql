@@ -1,11 +1,14 @@
 -- name: ExecutionsServiceGetDownloadLinkOrPathData :one
 SELECT
   executions.path AS path,
-  backups.is_local AS is_local,
+  (backups.is_local AND executions.destination_id IS NULL) AS is_local,
   destinations.bucket_name AS bucket_name,
   destinations.region AS region,
   destinations.endpoint AS endpoint,
   destinations.endpoint as destination_endpoint,
+  destinations.is_local AS destination_is_local,
+  destinations.local_path AS local_path,
+  destinations.use_path_style AS use_path_style,
   (
     CASE WHEN destinations.access_key IS NOT NULL
     THEN pgp_sym_decrypt(destinations.access_key, sqlc.arg('decryption_key')::TEXT)
@@ -20,5 +23,7 @@ SELECT
   ) AS decrypted_secret_key
 FROM executions
 INNER JOIN backups ON backups.id = executions.backup_id
-LEFT JOIN destinations ON destinations.id = backups.destination_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
 WHERE executions.id = @execution_id;
@@ -0,0 +1,139 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strings"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
+	"github.com/google/uuid"
+)
+
+// importableExtensions are the extensions of the dump formats that can be
+// restored: ZIP files created by PG Back Web, plain SQL dumps, gzipped dumps
+// and custom format dumps.
+var importableExtensions = []string{".zip", ".sql", ".gz", ".dump", ".backup"}
+
+// IsImportableFile returns true if the file name looks like a dump that can
+// be restored by PG Back Web.
+func IsImportableFile(filePath string) bool {
+	name := strings.ToLower(path.Base(filePath))
+	for _, ext := range importableExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportExecutions discovers the dump files stored under the given prefix of
+// the backup destination and registers them as successful executions of the
+// backup. If prefix is empty the backup destination directory is used.
+//
+// Files already registered by another execution in the same destination are
+// skipped. The size and timestamp of the executions are taken from the file
+// metadata.
+//
+// The timestamps of old files are usually past the retention of the backup,
+// so if pin is true the imported executions are pinned and the retention
+// rules don't delete them until they are unpinned.
+//
+// Returns the number of imported and skipped files.
+func (s *Service) ImportExecutions(
+	ctx context.Context, backupID uuid.UUID, prefix string, pin bool,
+) (int, int, error) {
+	back, err := s.dbgen.ExecutionsServiceGetBackupData(
+		ctx, dbgen.ExecutionsServiceGetBackupDataParams{
+			BackupID:      backupID,
+			EncryptionKey: s.env.PBW_ENCRYPTION_KEY,
+		},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if prefix == "" {
+		prefix = back.BackupDestDir
+	}
+
+	var files []storage.FileInfo
+	if back.BackupIsLocal || back.DestinationIsLocal.Bool {
+		files, err = s.ints.StorageClient.LocalListFiles(
+			back.DestinationLocalPath.String, prefix,
+		)
+	} else {
+		files, err = s.ints.StorageClient.S3ListFiles(
+			back.DecryptedDestinationAccessKey, back.DecryptedDestinationSecretKey,
+			back.DestinationRegion.String, back.DestinationEndpoint.String,
+			back.DestinationBucketName.String, prefix,
+			back.DestinationUsePathStyle.Bool,
+		)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	existingPaths, err := s.dbgen.ExecutionsServiceGetImportExistingPaths(
+		ctx, dbgen.ExecutionsServiceGetImportExistingPathsParams{
+			IsLocal:       back.BackupIsLocal,
+			DestinationID: back.BackupDestinationID,
+		},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	existing := map[string]bool{}
+	for _, p := range existingPaths {
+		existing[strutil.CreatePath(false, p.String)] = true
+	}
+
+	pinType, pinReason := sql.NullString{}, sql.NullString{}
+	if pin {
+		pinType = sql.NullString{Valid: true, String: PinTypePin}
+		pinReason = sql.NullString{Valid: true, String: "Imported from existing file"}
+	}
+
+	imported, skipped := 0, 0
+	for _, file := range files {
+		if !IsImportableFile(file.Path) {
+			continue
+		}
+		if existing[file.Path] {
+			skipped++
+			continue
+		}
+
+		_, err := s.dbgen.ExecutionsServiceImportExecution(
+			ctx, dbgen.ExecutionsServiceImportExecutionParams{
+				BackupID:   backupID,
+				Message:    sql.NullString{Valid: true, String: "Imported from existing file"},
+				Path:       sql.NullString{Valid: true, String: file.Path},
+				FileSize:   sql.NullInt64{Valid: true, Int64: file.Size},
+				StartedAt:  file.ModifiedAt,
+				FinishedAt: sql.NullTime{Valid: true, Time: file.ModifiedAt},
+				PinType:    pinType,
+				PinReason:  pinReason,
+			},
+		)
+		if err != nil {
+			return imported, skipped, err
+		}
+
+		existing[file.Path] = true
+		imported++
+	}
+
+	logger.Info("executions imported", logger.KV{
+		"backup_id": backupID.String(),
+		"prefix":    prefix,
+		"imported":  imported,
+		"skipped":   skipped,
+		"pinned":    pin,
+	})
+	return imported, skipped, nil
+}
@@ -0,0 +1,23 @@
+-- name: ExecutionsServiceGetImportExistingPaths :many
+SELECT executions.path
+FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+WHERE executions.path IS NOT NULL
+AND executions.status != 'deleted'
+AND (backups.is_local AND executions.destination_id IS NULL) = @is_local
+AND COALESCE(
+  executions.destination_id, backups.destination_id
+) IS NOT DISTINCT FROM sqlc.narg('destination_id')::UUID;
+
+-- name: ExecutionsServiceImportExecution :one
+INSERT INTO executions (
+  backup_id, destination_id, status, message, path, file_size, started_at,
+  finished_at, pin_type, pin_reason, pinned_at
+)
+SELECT
+  id, destination_id, 'success', @message, @path, @file_size, @started_at,
+  @finished_at, sqlc.narg('pin_type'), sqlc.narg('pin_reason'),
+  (CASE WHEN sqlc.narg('pin_type')::TEXT IS NULL THEN NULL ELSE NOW() END)
+FROM backups
+WHERE id = @backup_id
+RETURNING *;
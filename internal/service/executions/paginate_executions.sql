@@ -3,7 +3,9 @@ SELECT COUNT(executions.*)
 FROM executions
 INNER JOIN backups ON backups.id = executions.backup_id
 INNER JOIN databases ON databases.id = backups.database_id
-LEFT JOIN destinations ON destinations.id = backups.destination_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
 WHERE
 (
   sqlc.narg('backup_id')::UUID IS NULL
@@ -30,11 +32,32 @@ SELECT
   databases.name AS database_name,
   databases.pg_version AS database_pg_version,
   destinations.name AS destination_name,
-  backups.is_local AS backup_is_local
+  (
+    backups.is_local AND executions.destination_id IS NULL
+  ) AS backup_is_local,
+  destinations.is_local AS destination_is_local,
+  users.email AS pinned_by_email,
+  (
+    CASE WHEN executions.status = 'queued' AND (
+      executions.deferred_until IS NULL OR executions.deferred_until <= NOW()
+    ) THEN (
+      SELECT COUNT(*) FROM executions AS queue
+      WHERE queue.status = 'queued'
+      AND (queue.deferred_until IS NULL OR queue.deferred_until <= NOW())
+      AND queue.queued_at <= executions.queued_at
+    ) ELSE 0 END
+  )::INTEGER AS queue_position,
+  (
+    SELECT COUNT(*) FROM execution_downloads
+    WHERE execution_downloads.execution_id = executions.id
+  )::INTEGER AS downloads_qty
 FROM executions
 INNER JOIN backups ON backups.id = executions.backup_id
 INNER JOIN databases ON databases.id = backups.database_id
-LEFT JOIN destinations ON destinations.id = backups.destination_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
+LEFT JOIN users ON users.id = executions.pinned_by
 WHERE
 (
   sqlc.narg('backup_id')::UUID IS NULL
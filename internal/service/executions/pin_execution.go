@@ -0,0 +1,60 @@
+package executions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+)
+
+const (
+	PinTypePin       = "pin"
+	PinTypeLegalHold = "legal_hold"
+)
+
+// PinExecution pins an execution (or puts it under legal hold) so it is
+// never deleted, neither by the retention rules nor manually, until it is
+// unpinned.
+func (s *Service) PinExecution(
+	ctx context.Context, executionID, userID uuid.UUID, pinType, reason string,
+) error {
+	if pinType != PinTypePin && pinType != PinTypeLegalHold {
+		return fmt.Errorf("invalid pin type %q", pinType)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("a reason is required to pin an execution")
+	}
+
+	return s.dbgen.ExecutionsServicePinExecution(
+		ctx, dbgen.ExecutionsServicePinExecutionParams{
+			ID:        executionID,
+			PinType:   pinType,
+			PinReason: reason,
+			PinnedBy:  userID,
+		},
+	)
+}
+
+// UnpinExecution removes the pin or legal hold of an execution, so it is
+// handled again by the retention rules. Releasing a legal hold requires a
+// reason.
+func (s *Service) UnpinExecution(
+	ctx context.Context, executionID uuid.UUID, reason string,
+) error {
+	pinType, err := s.dbgen.ExecutionsServiceGetExecutionPinType(
+		ctx, executionID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if pinType.String == PinTypeLegalHold && strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("a reason is required to release a legal hold")
+	}
+
+	return s.dbgen.ExecutionsServiceUnpinExecution(ctx, executionID)
+}
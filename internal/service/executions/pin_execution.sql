@@ -0,0 +1,22 @@
+-- name: ExecutionsServicePinExecution :exec
+UPDATE executions
+SET
+  pin_type = @pin_type,
+  pin_reason = @pin_reason,
+  pinned_by = @pinned_by,
+  pinned_at = NOW()
+WHERE id = @id
+AND status != 'deleted';
+
+-- name: ExecutionsServiceUnpinExecution :exec
+UPDATE executions
+SET
+  pin_type = NULL,
+  pin_reason = NULL,
+  pinned_by = NULL,
+  pinned_at = NULL
+WHERE id = @id;
+
+-- name: ExecutionsServiceGetExecutionPinType :one
+SELECT pin_type FROM executions
+WHERE id = @id;
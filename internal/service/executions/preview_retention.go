@@ -0,0 +1,147 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+)
+
+// RetentionSettings are the retention settings of a backup that can be
+// previewed before saving them.
+type RetentionSettings struct {
+	TimeZone      string
+	RetentionDays int16
+	KeepDaily     int16
+	KeepWeekly    int16
+	KeepMonthly   int16
+	KeepYearly    int16
+	KeepLast      int16
+}
+
+// RetentionExpiration is an execution that will be deleted by the retention
+// rules of its backup.
+type RetentionExpiration struct {
+	BackupID    uuid.UUID     `json:"backup_id"`
+	BackupName  string        `json:"backup_name"`
+	ExecutionID uuid.UUID     `json:"execution_id"`
+	Status      string        `json:"status"`
+	FinishedAt  time.Time     `json:"finished_at"`
+	FileSize    sql.NullInt64 `json:"-"`
+	// ExpiresAt is the day in which the execution will be deleted, it is
+	// accurate to the day because the retention rules run periodically.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Immediate is true when the execution is deleted by the next retention
+	// run.
+	Immediate bool `json:"immediate"`
+}
+
+// PreviewRetention returns the executions of a backup that would be deleted
+// right away and over the next days if the given retention settings were
+// saved. It assumes no new executions are created in that period.
+func (s *Service) PreviewRetention(
+	ctx context.Context, backupID uuid.UUID, settings RetentionSettings, days int,
+) ([]RetentionExpiration, error) {
+	backup, err := s.dbgen.ExecutionsServiceGetRetentionBackup(ctx, backupID)
+	if err != nil {
+		return nil, err
+	}
+
+	executions, err := s.dbgen.ExecutionsServiceGetRetentionExecutions(
+		ctx, backupID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulateRetention(
+		dbgen.ExecutionsServiceGetRetentionBackupsRow{
+			ID:            backup.ID,
+			Name:          backup.Name,
+			TimeZone:      settings.TimeZone,
+			RetentionDays: settings.RetentionDays,
+			KeepDaily:     settings.KeepDaily,
+			KeepWeekly:    settings.KeepWeekly,
+			KeepMonthly:   settings.KeepMonthly,
+			KeepYearly:    settings.KeepYearly,
+			KeepLast:      settings.KeepLast,
+		},
+		executions, time.Now(), days,
+	), nil
+}
+
+// GetUpcomingExpirations returns the executions of all backups that will be
+// deleted by their retention rules over the next days, sorted by expiration.
+func (s *Service) GetUpcomingExpirations(
+	ctx context.Context, days int,
+) ([]RetentionExpiration, error) {
+	backups, err := s.dbgen.ExecutionsServiceGetRetentionBackups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expirations := []RetentionExpiration{}
+	for _, backup := range backups {
+		executions, err := s.dbgen.ExecutionsServiceGetRetentionExecutions(
+			ctx, backup.ID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		expirations = append(
+			expirations, simulateRetention(backup, executions, now, days)...,
+		)
+	}
+
+	sort.SliceStable(expirations, func(i, j int) bool {
+		return expirations[i].ExpiresAt.Before(expirations[j].ExpiresAt)
+	})
+
+	return expirations, nil
+}
+
+// simulateRetention evaluates the retention rules of a backup once per day
+// over the next days and returns the executions that expire in that period.
+func simulateRetention(
+	backup dbgen.ExecutionsServiceGetRetentionBackupsRow,
+	executions []dbgen.ExecutionsServiceGetRetentionExecutionsRow,
+	now time.Time,
+	days int,
+) []RetentionExpiration {
+	byID := map[uuid.UUID]dbgen.ExecutionsServiceGetRetentionExecutionsRow{}
+	for _, execution := range executions {
+		byID[execution.ID] = execution
+	}
+
+	seen := map[uuid.UUID]bool{}
+	expirations := []RetentionExpiration{}
+	for day := 0; day <= days; day++ {
+		at := now.Add(time.Duration(day) * 24 * time.Hour)
+
+		for _, id := range planRetention(backup, executions, at).Expired {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			execution := byID[id]
+			expirations = append(expirations, RetentionExpiration{
+				BackupID:    backup.ID,
+				BackupName:  backup.Name,
+				ExecutionID: id,
+				Status:      execution.Status,
+				FinishedAt:  execution.FinishedAt.Time,
+				FileSize:    execution.FileSize,
+				ExpiresAt:   at,
+				Immediate:   day == 0,
+			})
+		}
+	}
+
+	return expirations
+}
@@ -0,0 +1,129 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/google/uuid"
+)
+
+// InterruptedMessage is the message of the executions and restorations marked
+// as failed because the instance running them stopped before they finished.
+const InterruptedMessage = "Interrupted: the instance running it stopped before it finished"
+
+// ReconcileInterruptedExecutions marks as failed the running executions whose
+// instance is gone (it stopped sending heartbeats for the node timeout),
+// deletes their partial files and fires the failure webhooks.
+func (s *Service) ReconcileInterruptedExecutions() {
+	s.interruptExecutions(sql.NullString{})
+}
+
+// InterruptRunningExecutions marks as failed the executions still running in
+// this instance, it is used at shutdown once the grace period is over. If
+// one of them finishes afterwards its result is discarded and its file is
+// deleted, see runQueuedExecution.
+func (s *Service) InterruptRunningExecutions() {
+	s.interruptExecutions(
+		sql.NullString{Valid: true, String: s.clusterService.NodeID()},
+	)
+}
+
+// interruptExecutions marks the interrupted executions as failed, if nodeID
+// is set the running executions of that node are taken as interrupted.
+func (s *Service) interruptExecutions(nodeID sql.NullString) {
+	ctx := context.Background()
+
+	executions, err := s.dbgen.ExecutionsServiceGetInterruptedExecutions(
+		ctx, dbgen.ExecutionsServiceGetInterruptedExecutionsParams{
+			NodeID:             nodeID,
+			NodeTimeoutSeconds: int32(s.clusterService.NodeTimeout().Seconds()),
+		},
+	)
+	if err != nil {
+		logger.Error("error getting interrupted executions", logger.KV{
+			"error": err,
+		})
+		return
+	}
+
+	for _, execution := range executions {
+		marked, err := s.dbgen.ExecutionsServiceMarkExecutionInterrupted(
+			ctx, dbgen.ExecutionsServiceMarkExecutionInterruptedParams{
+				ID:      execution.ID,
+				Message: InterruptedMessage,
+			},
+		)
+		if err != nil {
+			logger.Error("error marking execution as interrupted", logger.KV{
+				"execution_id": execution.ID.String(),
+				"error":        err,
+			})
+			continue
+		}
+		if marked == 0 {
+			continue
+		}
+
+		logger.Info("interrupted execution marked as failed", logger.KV{
+			"execution_id": execution.ID.String(),
+			"backup_id":    execution.BackupID.String(),
+			"node_id":      execution.NodeID.String,
+		})
+		s.webhooksService.RunExecutionFailed(execution.BackupID)
+
+		s.deleteInterruptedExecutionFile(ctx, execution.ID)
+	}
+}
+
+// deleteInterruptedExecutionFile deletes the partial file of an interrupted
+// execution and aborts its incomplete S3 multipart uploads, whose parts are
+// not deleted with the file.
+func (s *Service) deleteInterruptedExecutionFile(
+	ctx context.Context, executionID uuid.UUID,
+) {
+	fileData, err := s.dbgen.ExecutionsServiceGetExecutionForSoftDelete(
+		ctx, dbgen.ExecutionsServiceGetExecutionForSoftDeleteParams{
+			ExecutionID:   executionID,
+			EncryptionKey: s.env.PBW_ENCRYPTION_KEY,
+		},
+	)
+	if err != nil {
+		logger.Error("error getting file of interrupted execution", logger.KV{
+			"execution_id": executionID.String(),
+			"error":        err,
+		})
+		return
+	}
+	if !fileData.ExecutionPath.Valid {
+		return
+	}
+
+	isLocal := fileData.BackupIsLocal || fileData.DestinationIsLocal.Bool
+	if !isLocal {
+		err := s.ints.StorageClient.S3AbortMultipartUploads(
+			fileData.DecryptedDestinationAccessKey, fileData.DecryptedDestinationSecretKey,
+			fileData.DestinationRegion.String, fileData.DestinationEndpoint.String,
+			fileData.DestinationBucketName.String, fileData.ExecutionPath.String,
+			fileData.DestinationUsePathStyle.Bool,
+		)
+		if err != nil {
+			logger.Error("error aborting uploads of interrupted execution", logger.KV{
+				"execution_id": executionID.String(),
+				"error":        err,
+			})
+		}
+	}
+
+	// The file may not exist if the instance stopped before creating it
+	err = s.deleteExecutionFile(fileData)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Error("error deleting partial file of interrupted execution", logger.KV{
+			"execution_id": executionID.String(),
+			"error":        err,
+		})
+	}
+}
@@ -0,0 +1,26 @@
+-- name: ExecutionsServiceGetInterruptedExecutions :many
+SELECT id, backup_id, node_id
+FROM executions
+WHERE status = 'running'
+AND (
+  CASE WHEN sqlc.narg('node_id')::TEXT IS NOT NULL
+  THEN executions.node_id = sqlc.narg('node_id')::TEXT
+  ELSE NOT EXISTS (
+    SELECT 1
+    FROM cluster_nodes
+    WHERE cluster_nodes.node_id = executions.node_id
+    AND cluster_nodes.heartbeat_at > NOW() - make_interval(
+      secs => @node_timeout_seconds::INTEGER
+    )
+  )
+  END
+);
+
+-- name: ExecutionsServiceMarkExecutionInterrupted :execrows
+UPDATE executions
+SET
+  status = 'failed',
+  message = @message,
+  finished_at = NOW()
+WHERE id = @id
+AND status = 'running';
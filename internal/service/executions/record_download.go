@@ -0,0 +1,25 @@
+package executions
+
+import (
+	"context"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+)
+
+// RecordDownload stores who downloaded the file of an execution, when, from
+// where and how, to audit the access to the backups.
+func (s *Service) RecordDownload(
+	ctx context.Context, params dbgen.ExecutionsServiceCreateDownloadParams,
+) error {
+	logger.Info("execution downloaded", logger.KV{
+		"execution_id": params.ExecutionID.String(),
+		"user_id":      params.UserID.UUID.String(),
+		"user_email":   params.UserEmail,
+		"ip":           params.Ip,
+		"mode":         params.Mode,
+		"range":        params.ByteRange.String,
+	})
+
+	return s.dbgen.ExecutionsServiceCreateDownload(ctx, params)
+}
@@ -0,0 +1,7 @@
+-- name: ExecutionsServiceCreateDownload :exec
+INSERT INTO execution_downloads (
+  execution_id, user_id, user_email, ip, mode, byte_range
+)
+VALUES (
+  @execution_id, @user_id, @user_email, @ip, @mode, sqlc.narg('byte_range')
+);
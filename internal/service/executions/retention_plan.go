@@ -0,0 +1,91 @@
+package executions
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/util/retentionutil"
+	"github.com/google/uuid"
+)
+
+// retentionPlan is the result of evaluating the retention rules of a backup.
+type retentionPlan struct {
+	// Keepers are the GFS labels (e.g. "daily, monthly") of the executions
+	// kept by the GFS policy.
+	Keepers map[uuid.UUID]string
+	// Expired are the executions that must be deleted.
+	Expired []uuid.UUID
+}
+
+// planRetention evaluates the retention rules of a backup over its finished
+// executions, that must be sorted from newest to oldest.
+//
+// Without keep rules (GFS or keep last) executions older than the retention
+// days expire. With keep rules the executions that are not kept by them
+// expire once they are older than the retention days, or right away if they
+// are 0.
+//
+// Pinned executions and the newest successful execution of the backup never
+// expire.
+func planRetention(
+	backup dbgen.ExecutionsServiceGetRetentionBackupsRow,
+	executions []dbgen.ExecutionsServiceGetRetentionExecutionsRow,
+	now time.Time,
+) retentionPlan {
+	policy := retentionutil.GFSPolicy{
+		Daily:   int(backup.KeepDaily),
+		Weekly:  int(backup.KeepWeekly),
+		Monthly: int(backup.KeepMonthly),
+		Yearly:  int(backup.KeepYearly),
+	}
+	hasKeepRules := policy.Enabled() || backup.KeepLast > 0
+
+	loc, err := time.LoadLocation(backup.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	candidates := []dbgen.ExecutionsServiceGetRetentionExecutionsRow{}
+	times := []time.Time{}
+	for _, execution := range executions {
+		if execution.Status == "success" && execution.Path.Valid {
+			candidates = append(candidates, execution)
+			times = append(times, execution.FinishedAt.Time)
+		}
+	}
+
+	plan := retentionPlan{Keepers: map[uuid.UUID]string{}}
+	for i, labels := range retentionutil.GFSKeepers(times, policy, loc) {
+		plan.Keepers[candidates[i].ID] = strings.Join(labels, ", ")
+	}
+
+	kept := map[uuid.UUID]bool{}
+	for id := range plan.Keepers {
+		kept[id] = true
+	}
+	for i, execution := range candidates {
+		if i < int(backup.KeepLast) {
+			kept[execution.ID] = true
+		}
+	}
+	if len(candidates) > 0 {
+		kept[candidates[0].ID] = true
+	}
+
+	retention := time.Duration(backup.RetentionDays) * 24 * time.Hour
+	for _, execution := range executions {
+		if kept[execution.ID] || execution.PinnedAt.Valid {
+			continue
+		}
+
+		isOld := backup.RetentionDays > 0 &&
+			execution.FinishedAt.Time.Add(retention).Before(now)
+
+		if isOld || (hasKeepRules && backup.RetentionDays == 0) {
+			plan.Expired = append(plan.Expired, execution.ID)
+		}
+	}
+
+	return plan
+}
@@ -0,0 +1,173 @@
+package executions
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var retentionNow = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+// retentionExecution returns a finished execution of the given age in days.
+func retentionExecution(
+	status string, daysAgo int, hasPath bool,
+) dbgen.ExecutionsServiceGetRetentionExecutionsRow {
+	finishedAt := retentionNow.Add(-time.Duration(daysAgo) * 24 * time.Hour)
+
+	return dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+		ID:         uuid.New(),
+		Status:     status,
+		Path:       sql.NullString{String: "dump.zip", Valid: hasPath},
+		FinishedAt: sql.NullTime{Time: finishedAt, Valid: true},
+	}
+}
+
+func pinned(
+	execution dbgen.ExecutionsServiceGetRetentionExecutionsRow,
+) dbgen.ExecutionsServiceGetRetentionExecutionsRow {
+	execution.PinnedAt = sql.NullTime{Time: retentionNow, Valid: true}
+	return execution
+}
+
+func TestPlanRetention(t *testing.T) {
+	newestSuccess := retentionExecution("success", 10, true)
+	oldSuccess := retentionExecution("success", 11, true)
+	oldFailed := retentionExecution("failed", 9, false)
+	pinnedSuccess := pinned(retentionExecution("success", 30, true))
+	pinnedFailed := pinned(retentionExecution("failed", 30, false))
+	recentSuccess := retentionExecution("success", 1, true)
+	recentFailed := retentionExecution("failed", 0, false)
+	recentNoPath := retentionExecution("success", 0, false)
+
+	tests := []struct {
+		name       string
+		backup     dbgen.ExecutionsServiceGetRetentionBackupsRow
+		executions []dbgen.ExecutionsServiceGetRetentionExecutionsRow
+		expired    []uuid.UUID
+	}{
+		{
+			name:   "Newest success survives after a long outage",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{RetentionDays: 7},
+			// The database was down for 10 days, every execution is old
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				oldFailed, newestSuccess, oldSuccess,
+			},
+			expired: []uuid.UUID{oldFailed.ID, oldSuccess.ID},
+		},
+		{
+			name:   "Pinned executions survive",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{RetentionDays: 7},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				recentSuccess, pinnedSuccess, pinnedFailed,
+			},
+			expired: nil,
+		},
+		{
+			name: "Keep last counts only successful executions with a path",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{
+				RetentionDays: 7, KeepLast: 2,
+			},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				recentFailed, recentNoPath, recentSuccess, newestSuccess, oldSuccess,
+			},
+			expired: []uuid.UUID{oldSuccess.ID},
+		},
+		{
+			name: "Keep rules with no retention days expire right away",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{
+				RetentionDays: 0, KeepLast: 1,
+			},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				recentFailed, recentSuccess, newestSuccess,
+			},
+			expired: []uuid.UUID{recentFailed.ID, newestSuccess.ID},
+		},
+		{
+			name:   "No retention days and no keep rules keep everything",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				recentFailed, newestSuccess, oldSuccess,
+			},
+			expired: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.backup.TimeZone = "UTC"
+			plan := planRetention(tt.backup, tt.executions, retentionNow)
+			assert.ElementsMatch(t, tt.expired, plan.Expired)
+		})
+	}
+}
+
+func TestSimulateRetention(t *testing.T) {
+	newest := retentionExecution("success", 0, true)
+	expiresSoon := retentionExecution("success", 5, true)
+	expiredNow := retentionExecution("failed", 8, false)
+	pinnedOld := pinned(retentionExecution("success", 20, true))
+
+	tests := []struct {
+		name       string
+		backup     dbgen.ExecutionsServiceGetRetentionBackupsRow
+		executions []dbgen.ExecutionsServiceGetRetentionExecutionsRow
+		days       int
+		expected   map[uuid.UUID]int
+	}{
+		{
+			name:   "Expirations are reported on their day",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{RetentionDays: 7},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				newest, expiresSoon, expiredNow, pinnedOld,
+			},
+			days: 5,
+			expected: map[uuid.UUID]int{
+				expiredNow.ID:  0,
+				expiresSoon.ID: 3,
+			},
+		},
+		{
+			name:   "The newest success never expires",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{RetentionDays: 1},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				newest,
+			},
+			days:     30,
+			expected: map[uuid.UUID]int{},
+		},
+		{
+			name: "Keep rules with no retention days expire right away",
+			backup: dbgen.ExecutionsServiceGetRetentionBackupsRow{
+				RetentionDays: 0, KeepLast: 1,
+			},
+			executions: []dbgen.ExecutionsServiceGetRetentionExecutionsRow{
+				newest, expiresSoon, pinnedOld,
+			},
+			days: 5,
+			expected: map[uuid.UUID]int{
+				expiresSoon.ID: 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.backup.TimeZone = "UTC"
+			expirations := simulateRetention(
+				tt.backup, tt.executions, retentionNow, tt.days,
+			)
+
+			got := map[uuid.UUID]int{}
+			for _, expiration := range expirations {
+				day := int(expiration.ExpiresAt.Sub(retentionNow).Hours() / 24)
+				got[expiration.ExecutionID] = day
+				assert.Equal(t, day == 0, expiration.Immediate)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
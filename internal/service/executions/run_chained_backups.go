@@ -0,0 +1,39 @@
+package executions
+
+import (
+	"context"
+
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/google/uuid"
+)
+
+// runChainedBackups runs the backups chained to run after the given backup,
+// according to the status its execution finished with. Backups chained with
+// the "success" condition only run if it succeeded, the ones chained with
+// the "always" condition run either way.
+func (s *Service) runChainedBackups(
+	ctx context.Context, backupID uuid.UUID, status string,
+) {
+	chained, err := s.dbgen.ExecutionsServiceGetChainedBackups(ctx, backupID)
+	if err != nil {
+		logger.Error("error getting chained backups", logger.KV{
+			"backup_id": backupID.String(),
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	for _, backup := range chained {
+		if backup.RunAfterCondition != "always" && status != "success" {
+			continue
+		}
+
+		logger.Info("running chained backup", logger.KV{
+			"backup_id":           backup.ID.String(),
+			"run_after_backup_id": backupID.String(),
+		})
+
+		// The errors are already logged by RunScheduledExecution
+		_ = s.RunScheduledExecution(ctx, backup.ID)
+	}
+}
@@ -0,0 +1,6 @@
+-- name: ExecutionsServiceGetChainedBackups :many
+SELECT id, run_after_condition
+FROM backups
+WHERE run_after_backup_id = @backup_id
+AND is_active = true
+ORDER BY created_at;
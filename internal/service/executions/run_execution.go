@@ -3,20 +3,62 @@ package executions
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/integration/postgres"
 	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/service/webhooks"
 	"github.com/eduardolat/pgbackweb/internal/util/strutil"
 	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
 	"github.com/google/uuid"
 )
 
-// RunExecution runs a backup execution
+// RunExecution queues a new execution of a backup, it starts as soon as the
+// concurrency limits allow it.
 func (s *Service) RunExecution(ctx context.Context, backupID uuid.UUID) error {
-	updateExec := func(params dbgen.ExecutionsServiceUpdateExecutionParams) error {
+	ex, err := s.dbgen.ExecutionsServiceQueueExecution(ctx, backupID)
+	if err != nil {
+		logger.Error("error queueing backup", logger.KV{
+			"backup_id": backupID.String(),
+			"error":     err.Error(),
+		})
+		return err
+	}
+
+	logger.Info("backup queued", logger.KV{
+		"backup_id":    backupID.String(),
+		"execution_id": ex.ID.String(),
+	})
+
+	s.DispatchQueue()
+	return nil
+}
+
+// runQueuedExecution runs an execution already claimed from the queue.
+func (s *Service) runQueuedExecution(
+	ctx context.Context, executionID uuid.UUID, backupID uuid.UUID,
+) error {
+	// The execution is finished only if it is still running, it may have been
+	// marked as interrupted by the reconciliation (the heartbeats of this
+	// instance stopped) or at shutdown once the grace period was over
+	updateExec := func(params dbgen.ExecutionsServiceFinishExecutionParams) error {
+		finished, err := s.dbgen.ExecutionsServiceFinishExecution(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if finished == 0 {
+			logger.Info("execution finished after being interrupted", logger.KV{
+				"backup_id":    backupID.String(),
+				"execution_id": executionID.String(),
+			})
+			s.deleteInterruptedExecutionFile(ctx, executionID)
+			return nil
+		}
+
 		if params.Status.String == "success" {
 			s.webhooksService.RunExecutionSuccess(backupID)
 		}
@@ -25,10 +67,8 @@ func (s *Service) RunExecution(ctx context.Context, backupID uuid.UUID) error {
 			s.webhooksService.RunExecutionFailed(backupID)
 		}
 
-		_, err := s.dbgen.ExecutionsServiceUpdateExecution(
-			ctx, params,
-		)
-		return err
+		s.runChainedBackups(ctx, backupID, params.Status.String)
+		return nil
 	}
 
 	logError := func(err error) {
@@ -46,28 +86,30 @@ func (s *Service) RunExecution(ctx context.Context, backupID uuid.UUID) error {
 	)
 	if err != nil {
 		logError(err)
-		return err
+		return updateExec(dbgen.ExecutionsServiceFinishExecutionParams{
+			ID:         executionID,
+			Status:     sql.NullString{Valid: true, String: "failed"},
+			Message:    sql.NullString{Valid: true, String: err.Error()},
+			FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		})
 	}
 
-	ex, err := s.CreateExecution(ctx, dbgen.ExecutionsServiceCreateExecutionParams{
-		BackupID: backupID,
-		Status:   "running",
-	})
-	if err != nil {
-		logError(err)
-		return err
-	}
+	isLocal := back.BackupIsLocal || back.DestinationIsLocal.Bool
+	localPath := back.DestinationLocalPath.String
 
-	if !back.BackupIsLocal {
-		err = s.ints.StorageClient.S3Test(
-			back.DecryptedDestinationAccessKey, back.DecryptedDestinationSecretKey,
-			back.DestinationRegion.String, back.DestinationEndpoint.String,
-			back.DestinationBucketName.String,
-		)
+	// S3 destinations are not tested before each run, the upload fails if the
+	// bucket is unreachable and the health checks test them periodically
+	if isLocal {
+		lowSpaceTargetID := webhooks.LocalBackupsTargetID
+		if !back.BackupIsLocal {
+			lowSpaceTargetID = back.BackupDestinationID.UUID
+		}
+
+		err = s.checkLocalSpace(ctx, backupID, localPath, lowSpaceTargetID)
 		if err != nil {
 			logError(err)
-			return updateExec(dbgen.ExecutionsServiceUpdateExecutionParams{
-				ID:         ex.ID,
+			return updateExec(dbgen.ExecutionsServiceFinishExecutionParams{
+				ID:         executionID,
 				Status:     sql.NullString{Valid: true, String: "failed"},
 				Message:    sql.NullString{Valid: true, String: err.Error()},
 				FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
@@ -78,8 +120,8 @@ func (s *Service) RunExecution(ctx context.Context, backupID uuid.UUID) error {
 	pgVersion, err := s.ints.PGClient.ParseVersion(back.DatabasePgVersion)
 	if err != nil {
 		logError(err)
-		return updateExec(dbgen.ExecutionsServiceUpdateExecutionParams{
-			ID:         ex.ID,
+		return updateExec(dbgen.ExecutionsServiceFinishExecutionParams{
+			ID:         executionID,
 			Status:     sql.NullString{Valid: true, String: "failed"},
 			Message:    sql.NullString{Valid: true, String: err.Error()},
 			FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
@@ -89,14 +131,43 @@ func (s *Service) RunExecution(ctx context.Context, backupID uuid.UUID) error {
 	err = s.ints.PGClient.Test(pgVersion, back.DecryptedDatabaseConnectionString)
 	if err != nil {
 		logError(err)
-		return updateExec(dbgen.ExecutionsServiceUpdateExecutionParams{
-			ID:         ex.ID,
+		return updateExec(dbgen.ExecutionsServiceFinishExecutionParams{
+			ID:         executionID,
 			Status:     sql.NullString{Valid: true, String: "failed"},
 			Message:    sql.NullString{Valid: true, String: err.Error()},
 			FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
 		})
 	}
 
+	date := time.Now().Format(timeutil.LayoutSlashYYYYMMDD)
+	file := fmt.Sprintf(
+		"dump-%s-%s.zip",
+		time.Now().Format(timeutil.LayoutYYYYMMDDHHMMSS),
+		uuid.NewString(),
+	)
+	path := strutil.CreatePath(false, back.BackupDestDir, date, file)
+
+	// The path is saved before storing the file, so the partial file can be
+	// cleaned up if the execution is interrupted. It is only saved if the
+	// execution is still running, a node taken as gone while it was busy
+	// must not upload a file nobody tracks
+	saved, err := s.dbgen.ExecutionsServiceSetRunningExecutionPath(
+		ctx, dbgen.ExecutionsServiceSetRunningExecutionPathParams{
+			ID:   executionID,
+			Path: sql.NullString{Valid: true, String: path},
+		},
+	)
+	if err != nil {
+		logError(err)
+	}
+	if err == nil && saved == 0 {
+		logger.Info("execution interrupted before storing its file", logger.KV{
+			"backup_id":    backupID.String(),
+			"execution_id": executionID.String(),
+		})
+		return nil
+	}
+
 	dumpReader := s.ints.PGClient.DumpZip(
 		pgVersion, back.DecryptedDatabaseConnectionString, postgres.DumpParams{
 			DataOnly:   back.BackupOptDataOnly,
@@ -105,60 +176,77 @@ func (s *Service) RunExecution(ctx context.Context, backupID uuid.UUID) error {
 			IfExists:   back.BackupOptIfExists,
 			Create:     back.BackupOptCreate,
 			NoComments: back.BackupOptNoComments,
-		},
-	)
 
-	date := time.Now().Format(timeutil.LayoutSlashYYYYMMDD)
-	file := fmt.Sprintf(
-		"dump-%s-%s.zip",
-		time.Now().Format(timeutil.LayoutYYYYMMDDHHMMSS),
-		uuid.NewString(),
+			ReadRateLimit: int64(back.BackupDumpRateLimit) * 1024,
+		},
 	)
-	path := strutil.CreatePath(false, back.BackupDestDir, date, file)
-	fileSize := int64(0)
 
-	if back.BackupIsLocal {
-		fileSize, err = s.ints.StorageClient.LocalUpload(path, dumpReader)
-		if err != nil {
-			logError(err)
-			return updateExec(dbgen.ExecutionsServiceUpdateExecutionParams{
-				ID:         ex.ID,
-				Status:     sql.NullString{Valid: true, String: "failed"},
-				Message:    sql.NullString{Valid: true, String: err.Error()},
-				Path:       sql.NullString{Valid: true, String: path},
-				FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
-			})
-		}
-	}
+	stats, err := s.storeFile(back, path, dumpReader)
+	if err != nil {
+		logError(err)
+		return updateExec(dbgen.ExecutionsServiceFinishExecutionParams{
+			ID:         executionID,
+			Status:     sql.NullString{Valid: true, String: "failed"},
+			Message:    sql.NullString{Valid: true, String: err.Error()},
+			Path:       sql.NullString{Valid: true, String: path},
+			FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
 
-	if !back.BackupIsLocal {
-		fileSize, err = s.ints.StorageClient.S3Upload(
-			back.DecryptedDestinationAccessKey, back.DecryptedDestinationSecretKey,
-			back.DestinationRegion.String, back.DestinationEndpoint.String,
-			back.DestinationBucketName.String, path, dumpReader,
-		)
-		if err != nil {
-			logError(err)
-			return updateExec(dbgen.ExecutionsServiceUpdateExecutionParams{
-				ID:         ex.ID,
-				Status:     sql.NullString{Valid: true, String: "failed"},
-				Message:    sql.NullString{Valid: true, String: err.Error()},
-				Path:       sql.NullString{Valid: true, String: path},
-				FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
-			})
-		}
+			UploadParts:   sql.NullInt32{Valid: true, Int32: int32(stats.Parts)},
+			UploadRetries: sql.NullInt32{Valid: true, Int32: int32(stats.Retries)},
+		})
 	}
 
 	logger.Info("backup created successfully", logger.KV{
-		"backup_id":    backupID.String(),
-		"execution_id": ex.ID.String(),
+		"backup_id":      backupID.String(),
+		"execution_id":   executionID.String(),
+		"upload_parts":   stats.Parts,
+		"upload_retries": stats.Retries,
 	})
-	return updateExec(dbgen.ExecutionsServiceUpdateExecutionParams{
-		ID:         ex.ID,
+	return updateExec(dbgen.ExecutionsServiceFinishExecutionParams{
+		ID:         executionID,
 		Status:     sql.NullString{Valid: true, String: "success"},
 		Message:    sql.NullString{Valid: true, String: "Backup created successfully"},
 		Path:       sql.NullString{Valid: true, String: path},
 		FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
-		FileSize:   sql.NullInt64{Valid: true, Int64: fileSize},
+		FileSize:   sql.NullInt64{Valid: true, Int64: stats.Size},
+
+		UploadParts:   sql.NullInt32{Valid: true, Int32: int32(stats.Parts)},
+		UploadRetries: sql.NullInt32{Valid: true, Int32: int32(stats.Retries)},
 	})
 }
+
+// checkLocalSpace tests the local path of a backup and checks that it has
+// enough free space for a new execution, using the size of the last
+// successful execution as an estimate. Failed runs and uploaded dumps are
+// not representative and are ignored, and without any successful execution
+// the space is not checked.
+//
+// If there isn't enough space the storage_low_space webhooks of the given
+// target are run, so the warning doesn't depend on the periodic tests.
+func (s *Service) checkLocalSpace(
+	ctx context.Context, backupID uuid.UUID, localPath string,
+	lowSpaceTargetID uuid.UUID,
+) error {
+	freeBytes, err := s.ints.StorageClient.LocalTest(localPath)
+	if err != nil {
+		return err
+	}
+
+	lastSize, err := s.dbgen.ExecutionsServiceGetLastExecutionSize(ctx, backupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if lastSize.Int64 > freeBytes {
+		s.webhooksService.RunStorageLowSpace(lowSpaceTargetID)
+		return fmt.Errorf(
+			"not enough free space in the destination, the last backup took %s and only %s are free",
+			strutil.FormatFileSize(lastSize.Int64), strutil.FormatFileSize(freeBytes),
+		)
+	}
+
+	return nil
+}
@@ -3,12 +3,14 @@ SELECT
   backups.is_active as backup_is_active,
   backups.is_local as backup_is_local,
   backups.dest_dir as backup_dest_dir,
+  backups.destination_id as backup_destination_id,
   backups.opt_data_only as backup_opt_data_only,
   backups.opt_schema_only as backup_opt_schema_only,
   backups.opt_clean as backup_opt_clean,
   backups.opt_if_exists as backup_opt_if_exists,
   backups.opt_create as backup_opt_create,	
   backups.opt_no_comments as backup_opt_no_comments,
+  backups.dump_rate_limit as backup_dump_rate_limit,
 
   pgp_sym_decrypt(databases.connection_string, @encryption_key) AS decrypted_database_connection_string,
   databases.pg_version as database_pg_version,
@@ -16,6 +18,17 @@ SELECT
   destinations.bucket_name as destination_bucket_name,
   destinations.region as destination_region,
   destinations.endpoint as destination_endpoint,
+  destinations.is_local as destination_is_local,
+  destinations.local_path as destination_local_path,
+  destinations.use_path_style as destination_use_path_style,
+  destinations.storage_class as destination_storage_class,
+  destinations.sse as destination_sse,
+  destinations.sse_kms_key_id as destination_sse_kms_key_id,
+  destinations.object_lock_mode as destination_object_lock_mode,
+  destinations.object_lock_days as destination_object_lock_days,
+  destinations.upload_rate_limit as destination_upload_rate_limit,
+  destinations.upload_part_size as destination_upload_part_size,
+  destinations.upload_concurrency as destination_upload_concurrency,
   (
     CASE WHEN destinations.access_key IS NOT NULL
     THEN pgp_sym_decrypt(destinations.access_key, @encryption_key)
@@ -32,3 +45,32 @@ FROM backups
 INNER JOIN databases ON backups.database_id = databases.id
 LEFT JOIN destinations ON backups.destination_id = destinations.id
 WHERE backups.id = @backup_id;
+
+-- name: ExecutionsServiceGetLastExecutionSize :one
+SELECT file_size
+FROM executions
+WHERE backup_id = @backup_id
+AND status = 'success'
+AND file_size > 0
+AND is_upload = false
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: ExecutionsServiceFinishExecution :execrows
+UPDATE executions
+SET
+  status = COALESCE(sqlc.narg('status'), status),
+  message = COALESCE(sqlc.narg('message'), message),
+  path = COALESCE(sqlc.narg('path'), path),
+  finished_at = COALESCE(sqlc.narg('finished_at'), finished_at),
+  file_size = COALESCE(sqlc.narg('file_size'), file_size),
+  upload_parts = COALESCE(sqlc.narg('upload_parts'), upload_parts),
+  upload_retries = COALESCE(sqlc.narg('upload_retries'), upload_retries)
+WHERE id = @id
+AND status = 'running';
+
+-- name: ExecutionsServiceSetRunningExecutionPath :execrows
+UPDATE executions
+SET path = @path
+WHERE id = @id
+AND status = 'running';
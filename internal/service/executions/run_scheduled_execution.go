@@ -0,0 +1,46 @@
+package executions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/google/uuid"
+)
+
+// RunScheduledExecution runs a scheduled or chained execution of a backup,
+// unless it is inside a blackout window, in which case it is skipped or
+// deferred until the window ends, and recorded in the executions history.
+func (s *Service) RunScheduledExecution(
+	ctx context.Context, backupID uuid.UUID,
+) error {
+	window, found, err := s.blackoutsService.GetBackupActiveWindow(
+		ctx, backupID, time.Now(),
+	)
+	if err != nil {
+		// A missed backup is worse than a backup inside a window
+		logger.Error("error checking blackout windows, running backup anyway", logger.KV{
+			"backup_id": backupID.String(),
+			"error":     err,
+		})
+	}
+	if err != nil || !found {
+		return s.RunExecution(ctx, backupID)
+	}
+
+	if window.Action == blackouts.ActionSkip {
+		return s.SkipExecution(ctx, backupID, fmt.Sprintf(
+			"Skipped by the blackout window %q", window.Name,
+		))
+	}
+
+	return s.DeferExecution(
+		ctx, backupID, window.EndsAt, fmt.Sprintf(
+			"Deferred by the blackout window %q until %s", window.Name,
+			window.EndsAt.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
+		),
+	)
+}
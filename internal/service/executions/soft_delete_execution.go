@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/google/uuid"
@@ -25,23 +27,47 @@ func (s *Service) SoftDeleteExecution(
 		return err
 	}
 
-	if execution.ExecutionPath.Valid && !execution.BackupIsLocal {
+	if execution.ExecutionPinType.Valid {
+		return fmt.Errorf(
+			"execution is pinned (%s), unpin it before deleting it",
+			strings.ReplaceAll(execution.ExecutionPinType.String, "_", " "),
+		)
+	}
+
+	if err := s.deleteExecutionFile(execution); err != nil {
+		return err
+	}
+
+	return s.dbgen.ExecutionsServiceSoftDeleteExecution(ctx, executionID)
+}
+
+// deleteExecutionFile deletes the file of an execution from its destination,
+// if the execution has one.
+func (s *Service) deleteExecutionFile(
+	execution dbgen.ExecutionsServiceGetExecutionForSoftDeleteRow,
+) error {
+	isLocal := execution.BackupIsLocal || execution.DestinationIsLocal.Bool
+
+	if execution.ExecutionPath.Valid && !isLocal {
 		err := s.ints.StorageClient.S3Delete(
 			execution.DecryptedDestinationAccessKey, execution.DecryptedDestinationSecretKey,
 			execution.DestinationRegion.String, execution.DestinationEndpoint.String,
 			execution.DestinationBucketName.String, execution.ExecutionPath.String,
+			execution.DestinationUsePathStyle.Bool,
 		)
 		if err != nil {
 			return err
 		}
 	}
 
-	if execution.ExecutionPath.Valid && execution.BackupIsLocal {
-		err := s.ints.StorageClient.LocalDelete(execution.ExecutionPath.String)
+	if execution.ExecutionPath.Valid && isLocal {
+		err := s.ints.StorageClient.LocalDelete(
+			execution.DestinationLocalPath.String, execution.ExecutionPath.String,
+		)
 		if err != nil {
 			return err
 		}
 	}
 
-	return s.dbgen.ExecutionsServiceSoftDeleteExecution(ctx, executionID)
+	return nil
 }
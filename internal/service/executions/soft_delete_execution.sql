@@ -2,13 +2,19 @@
 SELECT
   executions.id as execution_id,
   executions.path as execution_path,
+  executions.pin_type as execution_pin_type,
 
   backups.id as backup_id,
-  backups.is_local as backup_is_local,
+  (
+    backups.is_local AND executions.destination_id IS NULL
+  ) as backup_is_local,
 
   destinations.bucket_name as destination_bucket_name,
   destinations.region as destination_region,
   destinations.endpoint as destination_endpoint,
+  destinations.is_local as destination_is_local,
+  destinations.local_path as destination_local_path,
+  destinations.use_path_style as destination_use_path_style,
   (
     CASE WHEN destinations.access_key IS NOT NULL
     THEN pgp_sym_decrypt(destinations.access_key, sqlc.arg('encryption_key')::TEXT)
@@ -23,7 +29,9 @@ SELECT
   ) AS decrypted_destination_secret_key
 FROM executions
 INNER JOIN backups ON backups.id = executions.backup_id
-LEFT JOIN destinations ON destinations.id = backups.destination_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
 WHERE executions.id = @execution_id;
 
 -- name: ExecutionsServiceSoftDeleteExecution :exec
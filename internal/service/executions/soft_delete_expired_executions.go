@@ -2,14 +2,27 @@ package executions
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/logger"
 )
 
+// SoftDeleteExpiredExecutions applies the retention rules of every backup,
+// marking the GFS keepers and soft deleting the expired executions.
 func (s *Service) SoftDeleteExpiredExecutions() {
 	ctx := context.Background()
 
-	expiredExecutions, err := s.dbgen.ExecutionsServiceGetExpiredExecutions(ctx)
+	err := s.dbgen.ExecutionsServiceClearDisabledGFSKeepers(ctx)
+	if err != nil {
+		logger.Error(
+			"error clearing disabled gfs keepers",
+			logger.KV{"error": err},
+		)
+	}
+
+	backups, err := s.dbgen.ExecutionsServiceGetRetentionBackups(ctx)
 	if err != nil {
 		logger.Error(
 			"error soft deleting expired executions",
@@ -18,13 +31,47 @@ func (s *Service) SoftDeleteExpiredExecutions() {
 		return
 	}
 
-	for _, execution := range expiredExecutions {
-		if err := s.SoftDeleteExecution(ctx, execution.ID); err != nil {
+	for _, backup := range backups {
+		executions, err := s.dbgen.ExecutionsServiceGetRetentionExecutions(
+			ctx, backup.ID,
+		)
+		if err != nil {
 			logger.Error(
 				"error soft deleting expired executions",
-				logger.KV{"id": execution.ID.String(), "error": err},
+				logger.KV{"backup_id": backup.ID.String(), "error": err},
 			)
-			return
+			continue
+		}
+
+		plan := planRetention(backup, executions, time.Now())
+
+		for _, execution := range executions {
+			keeper := plan.Keepers[execution.ID]
+			if keeper == execution.GfsKeeper.String {
+				continue
+			}
+
+			err := s.dbgen.ExecutionsServiceSetGFSKeeper(
+				ctx, dbgen.ExecutionsServiceSetGFSKeeperParams{
+					ID:        execution.ID,
+					GfsKeeper: sql.NullString{Valid: keeper != "", String: keeper},
+				},
+			)
+			if err != nil {
+				logger.Error(
+					"error marking gfs keeper",
+					logger.KV{"id": execution.ID.String(), "error": err},
+				)
+			}
+		}
+
+		for _, id := range plan.Expired {
+			if err := s.SoftDeleteExecution(ctx, id); err != nil {
+				logger.Error(
+					"error soft deleting expired executions",
+					logger.KV{"id": id.String(), "error": err},
+				)
+			}
 		}
 	}
 
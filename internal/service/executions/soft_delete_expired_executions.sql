@@ -1,11 +1,42 @@
--- name: ExecutionsServiceGetExpiredExecutions :many
-SELECT executions.*
-FROM executions
-JOIN backups ON executions.backup_id = backups.id
+-- name: ExecutionsServiceGetRetentionBackups :many
+SELECT
+  id, name, time_zone, retention_days,
+  keep_daily, keep_weekly, keep_monthly, keep_yearly, keep_last
+FROM backups
 WHERE
-  backups.retention_days > 0
-  AND executions.status != 'deleted'
-  AND executions.finished_at IS NOT NULL
-  AND (
-    executions.finished_at + (backups.retention_days || ' days')::INTERVAL
-  ) < NOW();
+  retention_days > 0
+  OR keep_daily > 0
+  OR keep_weekly > 0
+  OR keep_monthly > 0
+  OR keep_yearly > 0
+  OR keep_last > 0;
+
+-- name: ExecutionsServiceGetRetentionExecutions :many
+SELECT id, status, path, file_size, finished_at, gfs_keeper, pinned_at
+FROM executions
+WHERE backup_id = @backup_id
+AND status != 'deleted'
+AND finished_at IS NOT NULL
+AND is_upload = false
+ORDER BY finished_at DESC;
+
+-- name: ExecutionsServiceSetGFSKeeper :exec
+UPDATE executions
+SET gfs_keeper = sqlc.narg('gfs_keeper')
+WHERE id = @id;
+
+-- name: ExecutionsServiceClearDisabledGFSKeepers :exec
+UPDATE executions
+SET gfs_keeper = NULL
+FROM backups
+WHERE backups.id = executions.backup_id
+AND executions.gfs_keeper IS NOT NULL
+AND backups.keep_daily = 0
+AND backups.keep_weekly = 0
+AND backups.keep_monthly = 0
+AND backups.keep_yearly = 0;
+
+-- name: ExecutionsServiceGetRetentionBackup :one
+SELECT id, name
+FROM backups
+WHERE id = @id;
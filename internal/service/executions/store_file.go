@@ -0,0 +1,48 @@
+package executions
+
+import (
+	"io"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
+	"github.com/eduardolat/pgbackweb/internal/util/rateutil"
+)
+
+// storeFile stores a file in the destination of a backup, locally or in S3
+// depending on the backup configuration.
+//
+// The upload limits of the destination (rate, part size and concurrency) are
+// applied.
+//
+// Returns the statistics of the upload, also when it fails.
+func (s *Service) storeFile(
+	back dbgen.ExecutionsServiceGetBackupDataRow, path string, reader io.Reader,
+) (storage.UploadStats, error) {
+	reader = rateutil.LimitReader(
+		reader, int64(back.DestinationUploadRateLimit.Int32)*1024,
+	)
+
+	if back.BackupIsLocal || back.DestinationIsLocal.Bool {
+		size, err := s.ints.StorageClient.LocalUpload(
+			back.DestinationLocalPath.String, path, reader,
+		)
+		return storage.UploadStats{Size: size}, err
+	}
+
+	return s.ints.StorageClient.S3Upload(
+		back.DecryptedDestinationAccessKey, back.DecryptedDestinationSecretKey,
+		back.DestinationRegion.String, back.DestinationEndpoint.String,
+		back.DestinationBucketName.String, path,
+		back.DestinationUsePathStyle.Bool, storage.S3UploadOptions{
+			StorageClass:   back.DestinationStorageClass.String,
+			SSE:            back.DestinationSse.String,
+			SSEKMSKeyID:    back.DestinationSseKmsKeyID.String,
+			ObjectLockMode: back.DestinationObjectLockMode.String,
+			ObjectLockDays: int(back.DestinationObjectLockDays.Int32),
+			PartSizeMB:     int(back.DestinationUploadPartSize.Int32),
+			Concurrency:    int(back.DestinationUploadConcurrency.Int32),
+			RetryWindow:    s.env.PBW_UPLOAD_RETRY_WINDOW,
+		},
+		reader,
+	)
+}
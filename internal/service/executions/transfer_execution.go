@@ -0,0 +1,279 @@
+package executions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/rateutil"
+	"github.com/google/uuid"
+)
+
+// errShuttingDown is returned when a transfer is started while the
+// application is shutting down.
+var errShuttingDown = errors.New(
+	"the application is shutting down, try again later",
+)
+
+// fileLocation holds the data needed to access a file stored locally or in
+// an S3 bucket.
+type fileLocation struct {
+	isLocal      bool
+	localPath    string
+	accessKey    string
+	secretKey    string
+	region       string
+	endpoint     string
+	bucketName   string
+	usePathStyle bool
+}
+
+func (s *Service) openLocationFile(
+	loc fileLocation, path string,
+) (io.ReadCloser, error) {
+	if loc.isLocal {
+		return os.Open(s.ints.StorageClient.LocalGetFullPath(loc.localPath, path))
+	}
+
+	obj, err := s.ints.StorageClient.S3Download(
+		loc.accessKey, loc.secretKey, loc.region, loc.endpoint, loc.bucketName,
+		path, loc.usePathStyle, "",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Body, nil
+}
+
+func (s *Service) deleteLocationFile(loc fileLocation, path string) error {
+	if loc.isLocal {
+		return s.ints.StorageClient.LocalDelete(loc.localPath, path)
+	}
+
+	return s.ints.StorageClient.S3Delete(
+		loc.accessKey, loc.secretKey, loc.region, loc.endpoint, loc.bucketName,
+		path, loc.usePathStyle,
+	)
+}
+
+// TransferExecution moves the file of a successful execution to another
+// destination: it copies the file, verifies its size and SHA-256 checksum,
+// points the execution to the new destination and deletes the original, so
+// restores keep working even if the backup now uses a different destination.
+//
+// The original file is always deleted, a copy left behind would not be
+// tracked by any execution and the destination audit would report it as an
+// orphan.
+//
+// Transfers are tracked like the running executions, so the shutdown waits
+// for them, and none is started while the application is shutting down.
+func (s *Service) TransferExecution(
+	ctx context.Context, executionID, destinationID uuid.UUID,
+) error {
+	if !s.running.Start() {
+		return errShuttingDown
+	}
+	defer s.running.Done()
+
+	src, err := s.dbgen.ExecutionsServiceGetTransferSource(
+		ctx, dbgen.ExecutionsServiceGetTransferSourceParams{
+			ExecutionID:   executionID,
+			EncryptionKey: s.env.PBW_ENCRYPTION_KEY,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if src.ExecutionStatus != "success" || !src.ExecutionPath.Valid {
+		return fmt.Errorf("only successful executions with a file can be transferred")
+	}
+
+	currentDestinationID := src.ExecutionDestinationID
+	if !currentDestinationID.Valid && !src.BackupIsLocal {
+		currentDestinationID = src.BackupDestinationID
+	}
+	if currentDestinationID.Valid && currentDestinationID.UUID == destinationID {
+		return fmt.Errorf("execution is already stored in this destination")
+	}
+
+	target, err := s.dbgen.ExecutionsServiceGetTransferTarget(
+		ctx, dbgen.ExecutionsServiceGetTransferTargetParams{
+			DestinationID: destinationID,
+			EncryptionKey: s.env.PBW_ENCRYPTION_KEY,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	path := src.ExecutionPath.String
+	srcLoc := fileLocation{
+		isLocal:      src.BackupIsLocal || src.DestinationIsLocal.Bool,
+		localPath:    src.DestinationLocalPath.String,
+		accessKey:    src.DecryptedDestinationAccessKey,
+		secretKey:    src.DecryptedDestinationSecretKey,
+		region:       src.DestinationRegion.String,
+		endpoint:     src.DestinationEndpoint.String,
+		bucketName:   src.DestinationBucketName.String,
+		usePathStyle: src.DestinationUsePathStyle.Bool,
+	}
+	targetLoc := fileLocation{
+		isLocal:      target.IsLocal,
+		localPath:    target.LocalPath.String,
+		accessKey:    target.DecryptedAccessKey,
+		secretKey:    target.DecryptedSecretKey,
+		region:       target.Region,
+		endpoint:     target.Endpoint,
+		bucketName:   target.BucketName,
+		usePathStyle: target.UsePathStyle,
+	}
+
+	reader, err := s.openLocationFile(srcLoc, path)
+	if err != nil {
+		return fmt.Errorf("error opening source file: %w", err)
+	}
+	defer reader.Close()
+
+	srcHash := sha256.New()
+	teeReader := rateutil.LimitReader(
+		io.TeeReader(reader, srcHash), int64(target.UploadRateLimit)*1024,
+	)
+
+	var written int64
+	if targetLoc.isLocal {
+		written, err = s.ints.StorageClient.LocalUpload(
+			targetLoc.localPath, path, teeReader,
+		)
+	} else {
+		var stats storage.UploadStats
+		stats, err = s.ints.StorageClient.S3Upload(
+			targetLoc.accessKey, targetLoc.secretKey, targetLoc.region,
+			targetLoc.endpoint, targetLoc.bucketName, path, targetLoc.usePathStyle,
+			storage.S3UploadOptions{
+				StorageClass:   target.StorageClass.String,
+				SSE:            target.Sse.String,
+				SSEKMSKeyID:    target.SseKmsKeyID.String,
+				ObjectLockMode: target.ObjectLockMode.String,
+				ObjectLockDays: int(target.ObjectLockDays.Int32),
+				PartSizeMB:     int(target.UploadPartSize),
+				Concurrency:    int(target.UploadConcurrency),
+				RetryWindow:    s.env.PBW_UPLOAD_RETRY_WINDOW,
+			},
+			teeReader,
+		)
+		written = stats.Size
+	}
+	if err != nil {
+		return fmt.Errorf("error storing file in target destination: %w", err)
+	}
+
+	if err := s.verifyTransferredFile(
+		targetLoc, path, written, src.ExecutionFileSize, srcHash.Sum(nil),
+	); err != nil {
+		if delErr := s.deleteLocationFile(targetLoc, path); delErr != nil {
+			logger.Error("error deleting unverified transferred file", logger.KV{
+				"execution_id":   executionID.String(),
+				"destination_id": destinationID.String(),
+				"error":          delErr.Error(),
+			})
+		}
+		return err
+	}
+
+	err = s.dbgen.ExecutionsServiceSetExecutionDestination(
+		ctx, dbgen.ExecutionsServiceSetExecutionDestinationParams{
+			ID:            executionID,
+			DestinationID: uuid.NullUUID{Valid: true, UUID: destinationID},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("execution transferred", logger.KV{
+		"execution_id":   executionID.String(),
+		"destination_id": destinationID.String(),
+		"size":           written,
+	})
+
+	if err := s.deleteLocationFile(srcLoc, path); err != nil {
+		return fmt.Errorf(
+			"file transferred but the original could not be deleted: %w", err,
+		)
+	}
+
+	return nil
+}
+
+// verifyTransferredFile reads back the transferred file and checks that its
+// size and SHA-256 checksum match the source file.
+func (s *Service) verifyTransferredFile(
+	loc fileLocation, path string, written int64, expectedSize sql.NullInt64,
+	expectedSum []byte,
+) error {
+	if expectedSize.Valid && expectedSize.Int64 != written {
+		return fmt.Errorf(
+			"size mismatch: expected %d bytes, transferred %d bytes",
+			expectedSize.Int64, written,
+		)
+	}
+
+	reader, err := s.openLocationFile(loc, path)
+	if err != nil {
+		return fmt.Errorf("error opening transferred file for verification: %w", err)
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return fmt.Errorf("error reading transferred file for verification: %w", err)
+	}
+
+	if size != written {
+		return fmt.Errorf(
+			"size mismatch: transferred %d bytes, stored %d bytes", written, size,
+		)
+	}
+	if !bytes.Equal(hash.Sum(nil), expectedSum) {
+		return fmt.Errorf("checksum mismatch between source and transferred file")
+	}
+
+	return nil
+}
+
+// TransferBackupExecutions transfers all the successful executions of a
+// backup that are not already stored in the given destination. It stops at
+// the first error.
+//
+// Returns the number of transferred executions.
+func (s *Service) TransferBackupExecutions(
+	ctx context.Context, backupID, destinationID uuid.UUID,
+) (int, error) {
+	ids, err := s.dbgen.ExecutionsServiceGetTransferableBackupExecutions(
+		ctx, dbgen.ExecutionsServiceGetTransferableBackupExecutionsParams{
+			BackupID:      backupID,
+			DestinationID: destinationID,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		if err := s.TransferExecution(ctx, id, destinationID); err != nil {
+			return i, fmt.Errorf("error transferring execution %s: %w", id, err)
+		}
+	}
+
+	return len(ids), nil
+}
@@ -0,0 +1,75 @@
+-- name: ExecutionsServiceGetTransferSource :one
+SELECT
+  executions.id as execution_id,
+  executions.status as execution_status,
+  executions.path as execution_path,
+  executions.file_size as execution_file_size,
+  executions.destination_id as execution_destination_id,
+  backups.destination_id as backup_destination_id,
+  (
+    backups.is_local AND executions.destination_id IS NULL
+  ) as backup_is_local,
+
+  destinations.bucket_name as destination_bucket_name,
+  destinations.region as destination_region,
+  destinations.endpoint as destination_endpoint,
+  destinations.is_local as destination_is_local,
+  destinations.local_path as destination_local_path,
+  destinations.use_path_style as destination_use_path_style,
+  (
+    CASE WHEN destinations.access_key IS NOT NULL
+    THEN pgp_sym_decrypt(destinations.access_key, sqlc.arg('encryption_key')::TEXT)
+    ELSE ''
+    END
+  ) AS decrypted_destination_access_key,
+  (
+    CASE WHEN destinations.secret_key IS NOT NULL
+    THEN pgp_sym_decrypt(destinations.secret_key, sqlc.arg('encryption_key')::TEXT)
+    ELSE ''
+    END
+  ) AS decrypted_destination_secret_key
+FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+LEFT JOIN destinations ON destinations.id = COALESCE(
+  executions.destination_id, backups.destination_id
+)
+WHERE executions.id = @execution_id;
+
+-- name: ExecutionsServiceGetTransferTarget :one
+SELECT
+  destinations.*,
+  (
+    CASE WHEN destinations.access_key IS NOT NULL
+    THEN pgp_sym_decrypt(destinations.access_key, sqlc.arg('encryption_key')::TEXT)
+    ELSE ''
+    END
+  ) AS decrypted_access_key,
+  (
+    CASE WHEN destinations.secret_key IS NOT NULL
+    THEN pgp_sym_decrypt(destinations.secret_key, sqlc.arg('encryption_key')::TEXT)
+    ELSE ''
+    END
+  ) AS decrypted_secret_key
+FROM destinations
+WHERE destinations.id = @destination_id;
+
+-- name: ExecutionsServiceSetExecutionDestination :exec
+UPDATE executions
+SET
+  destination_id = @destination_id,
+  lifecycle_attempts = 0,
+  lifecycle_error = NULL,
+  lifecycle_retry_at = NULL
+WHERE id = @id;
+
+-- name: ExecutionsServiceGetTransferableBackupExecutions :many
+SELECT executions.id
+FROM executions
+INNER JOIN backups ON backups.id = executions.backup_id
+WHERE executions.backup_id = @backup_id
+AND executions.status = 'success'
+AND executions.path IS NOT NULL
+AND COALESCE(
+  executions.destination_id, backups.destination_id
+) IS DISTINCT FROM @destination_id::UUID
+ORDER BY executions.started_at DESC;
@@ -6,6 +6,8 @@ SET
   path = COALESCE(sqlc.narg('path'), path),
   finished_at = COALESCE(sqlc.narg('finished_at'), finished_at),
   deleted_at = COALESCE(sqlc.narg('deleted_at'), deleted_at),
-  file_size = COALESCE(sqlc.narg('file_size'), file_size)
+  file_size = COALESCE(sqlc.narg('file_size'), file_size),
+  upload_parts = COALESCE(sqlc.narg('upload_parts'), upload_parts),
+  upload_retries = COALESCE(sqlc.narg('upload_retries'), upload_retries)
 WHERE id = @id
 RETURNING *;
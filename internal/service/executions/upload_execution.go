@@ -0,0 +1,146 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/google/uuid"
+)
+
+// UploadExtensions are the file extensions accepted for uploaded dumps.
+var UploadExtensions = []string{".sql", ".zip", ".gz", ".dump", ".backup"}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// UploadExecution stores an external dump file in the given destination, or
+// in the destination of the backup when it's not set, and registers it as a
+// successful execution of the backup, so it can be restored using the same
+// flow as any other execution.
+//
+// Uploaded executions are flagged so they are not taken into account by the
+// retention policies, the free space estimate or the missed runs catch-up.
+//
+// Supported formats are plain SQL, ZIP (with a dump.sql file inside), gzip
+// and pg_dump custom format.
+func (s *Service) UploadExecution(
+	ctx context.Context, backupID uuid.UUID, destinationID uuid.NullUUID,
+	fileName string, reader io.Reader,
+) (dbgen.Execution, error) {
+	supported := false
+	for _, ext := range UploadExtensions {
+		if strings.HasSuffix(strings.ToLower(fileName), ext) {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return dbgen.Execution{}, fmt.Errorf(
+			"unsupported file %s, allowed extensions are %s",
+			fileName, strings.Join(UploadExtensions, ", "),
+		)
+	}
+
+	back, err := s.dbgen.ExecutionsServiceGetBackupData(
+		ctx, dbgen.ExecutionsServiceGetBackupDataParams{
+			BackupID:      backupID,
+			EncryptionKey: s.env.PBW_ENCRYPTION_KEY,
+		},
+	)
+	if err != nil {
+		return dbgen.Execution{}, err
+	}
+
+	if destinationID.Valid {
+		dest, err := s.dbgen.ExecutionsServiceGetTransferTarget(
+			ctx, dbgen.ExecutionsServiceGetTransferTargetParams{
+				DestinationID: destinationID.UUID,
+				EncryptionKey: s.env.PBW_ENCRYPTION_KEY,
+			},
+		)
+		if err != nil {
+			return dbgen.Execution{}, err
+		}
+		back = withUploadDestination(back, dest)
+	}
+
+	ex, err := s.dbgen.ExecutionsServiceCreateUploadExecution(
+		ctx, dbgen.ExecutionsServiceCreateUploadExecutionParams{
+			BackupID:      backupID,
+			DestinationID: destinationID,
+			Message:       sql.NullString{Valid: true, String: "Uploading " + fileName},
+		},
+	)
+	if err != nil {
+		return dbgen.Execution{}, err
+	}
+
+	date := time.Now().Format(timeutil.LayoutSlashYYYYMMDD)
+	file := fmt.Sprintf(
+		"upload-%s-%s",
+		time.Now().Format(timeutil.LayoutYYYYMMDDHHMMSS),
+		unsafeFileNameChars.ReplaceAllString(fileName, "_"),
+	)
+	path := strutil.CreatePath(false, back.BackupDestDir, "uploads", date, file)
+
+	stats, err := s.storeFile(back, path, reader)
+	if err != nil {
+		_, _ = s.UpdateExecution(ctx, dbgen.ExecutionsServiceUpdateExecutionParams{
+			ID:         ex.ID,
+			Status:     sql.NullString{Valid: true, String: "failed"},
+			Message:    sql.NullString{Valid: true, String: err.Error()},
+			Path:       sql.NullString{Valid: true, String: path},
+			FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
+
+			UploadParts:   sql.NullInt32{Valid: true, Int32: int32(stats.Parts)},
+			UploadRetries: sql.NullInt32{Valid: true, Int32: int32(stats.Retries)},
+		})
+		return dbgen.Execution{}, err
+	}
+
+	return s.UpdateExecution(ctx, dbgen.ExecutionsServiceUpdateExecutionParams{
+		ID:         ex.ID,
+		Status:     sql.NullString{Valid: true, String: "success"},
+		Message:    sql.NullString{Valid: true, String: "Uploaded from " + fileName},
+		Path:       sql.NullString{Valid: true, String: path},
+		FinishedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		FileSize:   sql.NullInt64{Valid: true, Int64: stats.Size},
+
+		UploadParts:   sql.NullInt32{Valid: true, Int32: int32(stats.Parts)},
+		UploadRetries: sql.NullInt32{Valid: true, Int32: int32(stats.Retries)},
+	})
+}
+
+// withUploadDestination replaces the destination of the backup data with the
+// given one, so the uploaded file is stored there.
+func withUploadDestination(
+	back dbgen.ExecutionsServiceGetBackupDataRow,
+	dest dbgen.ExecutionsServiceGetTransferTargetRow,
+) dbgen.ExecutionsServiceGetBackupDataRow {
+	back.BackupIsLocal = false
+	back.BackupDestinationID = uuid.NullUUID{Valid: true, UUID: dest.ID}
+	back.DestinationBucketName = sql.NullString{Valid: true, String: dest.BucketName}
+	back.DestinationRegion = sql.NullString{Valid: true, String: dest.Region}
+	back.DestinationEndpoint = sql.NullString{Valid: true, String: dest.Endpoint}
+	back.DestinationIsLocal = sql.NullBool{Valid: true, Bool: dest.IsLocal}
+	back.DestinationLocalPath = dest.LocalPath
+	back.DestinationUsePathStyle = sql.NullBool{Valid: true, Bool: dest.UsePathStyle}
+	back.DestinationStorageClass = dest.StorageClass
+	back.DestinationSse = dest.Sse
+	back.DestinationSseKmsKeyID = dest.SseKmsKeyID
+	back.DestinationObjectLockMode = dest.ObjectLockMode
+	back.DestinationObjectLockDays = dest.ObjectLockDays
+	back.DestinationUploadRateLimit = sql.NullInt32{Valid: true, Int32: dest.UploadRateLimit}
+	back.DestinationUploadPartSize = sql.NullInt32{Valid: true, Int32: dest.UploadPartSize}
+	back.DestinationUploadConcurrency = sql.NullInt32{Valid: true, Int32: dest.UploadConcurrency}
+	back.DecryptedDestinationAccessKey = dest.DecryptedAccessKey
+	back.DecryptedDestinationSecretKey = dest.DecryptedSecretKey
+	return back
+}
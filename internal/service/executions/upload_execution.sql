@@ -0,0 +1,8 @@
+-- name: ExecutionsServiceCreateUploadExecution :one
+INSERT INTO executions (backup_id, destination_id, status, message, is_upload)
+SELECT
+  id, COALESCE(sqlc.narg('destination_id')::UUID, destination_id),
+  'running', @message, true
+FROM backups
+WHERE id = @backup_id
+RETURNING *;
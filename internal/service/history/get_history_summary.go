@@ -0,0 +1,43 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+)
+
+// HistorySummary holds the number of rows purged in the last 30 days.
+type HistorySummary struct {
+	// Enabled is false when PBW_HISTORY_RETENTION_DAYS is 0.
+	Enabled       bool
+	RetentionDays int
+	Purged        dbgen.HistoryServiceGetPurgedQtyRow
+	LastPurgeAt   sql.NullTime
+}
+
+// GetHistorySummary returns the number of rows purged in the last 30 days
+// and the date of the last purge.
+func (s *Service) GetHistorySummary(ctx context.Context) (HistorySummary, error) {
+	summary := HistorySummary{
+		Enabled:       s.env.PBW_HISTORY_RETENTION_DAYS > 0,
+		RetentionDays: s.env.PBW_HISTORY_RETENTION_DAYS,
+	}
+
+	purged, err := s.dbgen.HistoryServiceGetPurgedQty(ctx)
+	if err != nil {
+		return summary, err
+	}
+	summary.Purged = purged
+
+	lastPurge, err := s.dbgen.HistoryServiceGetLastPurge(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return summary, err
+	}
+	if err == nil {
+		summary.LastPurgeAt = sql.NullTime{Time: lastPurge.CreatedAt, Valid: true}
+	}
+
+	return summary, nil
+}
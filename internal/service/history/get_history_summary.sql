@@ -0,0 +1,12 @@
+-- name: HistoryServiceGetPurgedQty :one
+SELECT
+  COALESCE(SUM(executions_deleted), 0)::BIGINT AS executions_deleted,
+  COALESCE(SUM(restorations_deleted), 0)::BIGINT AS restorations_deleted,
+  COALESCE(SUM(webhook_executions_deleted), 0)::BIGINT AS webhook_executions_deleted
+FROM history_purges
+WHERE created_at > NOW() - INTERVAL '30 days';
+
+-- name: HistoryServiceGetLastPurge :one
+SELECT * FROM history_purges
+ORDER BY created_at DESC
+LIMIT 1;
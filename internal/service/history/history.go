@@ -0,0 +1,18 @@
+package history
+
+import (
+	"github.com/eduardolat/pgbackweb/internal/config"
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+)
+
+type Service struct {
+	env   config.Env
+	dbgen *dbgen.Queries
+}
+
+func New(env config.Env, dbgen *dbgen.Queries) *Service {
+	return &Service{
+		env:   env,
+		dbgen: dbgen,
+	}
+}
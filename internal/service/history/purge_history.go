@@ -0,0 +1,104 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+)
+
+// purgeBatchSize is the maximum number of rows deleted by a single query, so
+// big tables are purged without long locks.
+const purgeBatchSize = 5000
+
+// PurgeHistory hard deletes the soft deleted executions, restorations and
+// webhook executions older than PBW_HISTORY_RETENTION_DAYS. It does nothing
+// if the history retention is disabled.
+func (s *Service) PurgeHistory() {
+	if s.env.PBW_HISTORY_RETENTION_DAYS <= 0 {
+		return
+	}
+
+	ctx := context.Background()
+	threshold := time.Now().AddDate(0, 0, -s.env.PBW_HISTORY_RETENTION_DAYS)
+
+	purge := func(
+		name string,
+		fn func(ctx context.Context, threshold time.Time, batchSize int32) (int64, error),
+	) int64 {
+		var total int64
+		for {
+			deleted, err := fn(ctx, threshold, purgeBatchSize)
+			if err != nil {
+				logger.Error("error purging history", logger.KV{
+					"table": name,
+					"error": err,
+				})
+				return total
+			}
+
+			total += deleted
+			if deleted < purgeBatchSize {
+				return total
+			}
+		}
+	}
+
+	executions := purge(
+		"executions",
+		func(ctx context.Context, threshold time.Time, batchSize int32) (int64, error) {
+			return s.dbgen.HistoryServicePurgeDeletedExecutions(
+				ctx, dbgen.HistoryServicePurgeDeletedExecutionsParams{
+					Threshold: threshold,
+					BatchSize: batchSize,
+				},
+			)
+		},
+	)
+	restorations := purge(
+		"restorations",
+		func(ctx context.Context, threshold time.Time, batchSize int32) (int64, error) {
+			return s.dbgen.HistoryServicePurgeRestorations(
+				ctx, dbgen.HistoryServicePurgeRestorationsParams{
+					Threshold: threshold,
+					BatchSize: batchSize,
+				},
+			)
+		},
+	)
+	webhookExecutions := purge(
+		"webhook_executions",
+		func(ctx context.Context, threshold time.Time, batchSize int32) (int64, error) {
+			return s.dbgen.HistoryServicePurgeWebhookExecutions(
+				ctx, dbgen.HistoryServicePurgeWebhookExecutionsParams{
+					Threshold: threshold,
+					BatchSize: batchSize,
+				},
+			)
+		},
+	)
+
+	err := s.dbgen.HistoryServiceCreatePurge(
+		ctx, dbgen.HistoryServiceCreatePurgeParams{
+			Threshold:                threshold,
+			ExecutionsDeleted:        executions,
+			RestorationsDeleted:      restorations,
+			WebhookExecutionsDeleted: webhookExecutions,
+		},
+	)
+	if err != nil {
+		logger.Error("error saving history purge", logger.KV{"error": err})
+	}
+
+	if err := s.dbgen.HistoryServiceDeleteOldPurges(ctx); err != nil {
+		logger.Error("error deleting old history purges", logger.KV{"error": err})
+	}
+
+	logger.Info("history purged", logger.KV{
+		"threshold":          threshold,
+		"executions":         executions,
+		"restorations":       restorations,
+		"webhook_executions": webhookExecutions,
+	})
+}
@@ -0,0 +1,39 @@
+-- name: HistoryServicePurgeDeletedExecutions :execrows
+DELETE FROM executions
+WHERE id IN (
+  SELECT id FROM executions
+  WHERE status = 'deleted'
+  AND deleted_at < @threshold
+  LIMIT @batch_size
+);
+
+-- name: HistoryServicePurgeRestorations :execrows
+DELETE FROM restorations
+WHERE id IN (
+  SELECT id FROM restorations
+  WHERE status != 'running'
+  AND started_at < @threshold
+  LIMIT @batch_size
+);
+
+-- name: HistoryServicePurgeWebhookExecutions :execrows
+DELETE FROM webhook_executions
+WHERE id IN (
+  SELECT id FROM webhook_executions
+  WHERE created_at < @threshold
+  LIMIT @batch_size
+);
+
+-- name: HistoryServiceCreatePurge :exec
+INSERT INTO history_purges (
+  threshold, executions_deleted, restorations_deleted,
+  webhook_executions_deleted
+)
+VALUES (
+  @threshold, @executions_deleted, @restorations_deleted,
+  @webhook_executions_deleted
+);
+
+-- name: HistoryServiceDeleteOldPurges :exec
+DELETE FROM history_purges
+WHERE created_at < NOW() - INTERVAL '90 days';
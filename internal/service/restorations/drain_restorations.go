@@ -0,0 +1,9 @@
+package restorations
+
+import "context"
+
+// DrainRestorations stops accepting new restorations in this instance and
+// waits until the running ones finish or the context is done.
+func (s *Service) DrainRestorations(ctx context.Context) error {
+	return s.running.Drain(ctx)
+}
@@ -0,0 +1,50 @@
+package restorations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/service/executions"
+)
+
+// ReconcileInterruptedRestorations marks as failed the running restorations
+// whose instance is gone (it stopped sending heartbeats).
+func (s *Service) ReconcileInterruptedRestorations() {
+	s.interruptRestorations(sql.NullString{})
+}
+
+// InterruptRunningRestorations marks as failed the restorations still running
+// in this instance, it is used at shutdown once the grace period is over.
+func (s *Service) InterruptRunningRestorations() {
+	s.interruptRestorations(
+		sql.NullString{Valid: true, String: s.clusterService.NodeID()},
+	)
+}
+
+// interruptRestorations marks the interrupted restorations as failed, if
+// nodeID is set the running restorations of that node are taken as
+// interrupted.
+func (s *Service) interruptRestorations(nodeID sql.NullString) {
+	marked, err := s.dbgen.RestorationsServiceMarkInterruptedRestorations(
+		context.Background(),
+		dbgen.RestorationsServiceMarkInterruptedRestorationsParams{
+			Message:            executions.InterruptedMessage,
+			NodeID:             nodeID,
+			NodeTimeoutSeconds: int32(s.clusterService.NodeTimeout().Seconds()),
+		},
+	)
+	if err != nil {
+		logger.Error("error marking interrupted restorations", logger.KV{
+			"error": err,
+		})
+		return
+	}
+
+	if marked > 0 {
+		logger.Info("interrupted restorations marked as failed", logger.KV{
+			"restorations": marked,
+		})
+	}
+}
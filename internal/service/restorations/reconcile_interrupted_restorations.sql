@@ -0,0 +1,20 @@
+-- name: RestorationsServiceMarkInterruptedRestorations :execrows
+UPDATE restorations
+SET
+  status = 'failed',
+  message = @message,
+  finished_at = NOW()
+WHERE status = 'running'
+AND (
+  CASE WHEN sqlc.narg('node_id')::TEXT IS NOT NULL
+  THEN restorations.node_id = sqlc.narg('node_id')::TEXT
+  ELSE NOT EXISTS (
+    SELECT 1
+    FROM cluster_nodes
+    WHERE cluster_nodes.node_id = restorations.node_id
+    AND cluster_nodes.heartbeat_at > NOW() - make_interval(
+      secs => @node_timeout_seconds::INTEGER
+    )
+  )
+  END
+);
@@ -3,9 +3,11 @@ package restorations
 import (
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/integration"
+	"github.com/eduardolat/pgbackweb/internal/service/cluster"
 	"github.com/eduardolat/pgbackweb/internal/service/databases"
 	"github.com/eduardolat/pgbackweb/internal/service/destinations"
 	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/eduardolat/pgbackweb/internal/util/drainutil"
 )
 
 type Service struct {
@@ -14,12 +16,16 @@ type Service struct {
 	executionsService   *executions.Service
 	databasesService    *databases.Service
 	destinationsService *destinations.Service
+	clusterService      *cluster.Service
+
+	// running tracks the restorations running in this instance
+	running drainutil.Tracker
 }
 
 func New(
 	dbgen *dbgen.Queries, ints *integration.Integration,
 	executionsService *executions.Service, databasesService *databases.Service,
-	destinationsService *destinations.Service,
+	destinationsService *destinations.Service, clusterService *cluster.Service,
 ) *Service {
 	return &Service{
 		dbgen:               dbgen,
@@ -27,5 +33,6 @@ func New(
 		executionsService:   executionsService,
 		databasesService:    databasesService,
 		destinationsService: destinationsService,
+		clusterService:      clusterService,
 	}
 }
@@ -3,6 +3,7 @@ package restorations
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,12 +12,60 @@ import (
 	"github.com/google/uuid"
 )
 
+// errShuttingDown is returned when a restoration is started while the
+// application is shutting down.
+var errShuttingDown = errors.New(
+	"the application is shutting down, try again later",
+)
+
 // RunRestoration runs a backup restoration
 func (s *Service) RunRestoration(
 	ctx context.Context,
 	executionID uuid.UUID,
 	databaseID uuid.NullUUID,
 	connString string,
+) error {
+	// The application is shutting down, the restoration is recorded as failed
+	// so the user knows it has to be started again
+	if !s.running.Start() {
+		logRestorationError(executionID, databaseID, errShuttingDown)
+		_, err := s.CreateRestoration(ctx, dbgen.RestorationsServiceCreateRestorationParams{
+			ExecutionID: executionID,
+			DatabaseID:  databaseID,
+			Status:      "failed",
+			Message:     sql.NullString{Valid: true, String: errShuttingDown.Error()},
+		})
+		if err != nil {
+			logRestorationError(executionID, databaseID, err)
+		}
+		return errShuttingDown
+	}
+	defer s.running.Done()
+
+	return s.runRestoration(ctx, executionID, databaseID, connString)
+}
+
+func logRestorationError(
+	executionID uuid.UUID, databaseID uuid.NullUUID, err error,
+) {
+	dbID := "empty"
+	if databaseID.Valid {
+		dbID = databaseID.UUID.String()
+	}
+	logger.Error("error running restoration", logger.KV{
+		"execution_id": executionID.String(),
+		"database_id":  dbID,
+		"error":        err.Error(),
+	})
+}
+
+// runRestoration runs a backup restoration already registered in the
+// running tracker.
+func (s *Service) runRestoration(
+	ctx context.Context,
+	executionID uuid.UUID,
+	databaseID uuid.NullUUID,
+	connString string,
 ) error {
 	updateRes := func(params dbgen.RestorationsServiceUpdateRestorationParams) error {
 		_, err := s.dbgen.RestorationsServiceUpdateRestoration(
@@ -26,21 +75,14 @@ func (s *Service) RunRestoration(
 	}
 
 	logError := func(err error) {
-		dbID := "empty"
-		if databaseID.Valid {
-			dbID = databaseID.UUID.String()
-		}
-		logger.Error("error running restoration", logger.KV{
-			"execution_id": executionID.String(),
-			"database_id":  dbID,
-			"error":        err.Error(),
-		})
+		logRestorationError(executionID, databaseID, err)
 	}
 
 	res, err := s.CreateRestoration(ctx, dbgen.RestorationsServiceCreateRestorationParams{
 		ExecutionID: executionID,
 		DatabaseID:  databaseID,
 		Status:      "running",
+		NodeID:      sql.NullString{Valid: true, String: s.clusterService.NodeID()},
 	})
 	if err != nil {
 		logError(err)
@@ -116,7 +158,7 @@ func (s *Service) RunRestoration(
 		})
 	}
 
-	isLocal, zipURLOrPath, err := s.executionsService.GetExecutionDownloadLinkOrPath(
+	isLocal, dumpURLOrPath, err := s.executionsService.GetExecutionDownloadLinkOrPath(
 		ctx, executionID,
 	)
 	if err != nil {
@@ -129,8 +171,8 @@ func (s *Service) RunRestoration(
 		})
 	}
 
-	err = s.ints.PGClient.RestoreZip(
-		pgVersion, connString, isLocal, zipURLOrPath,
+	err = s.ints.PGClient.Restore(
+		pgVersion, connString, isLocal, dumpURLOrPath, execution.Path.String,
 	)
 	if err != nil {
 		logError(err)
@@ -0,0 +1,44 @@
+package restorations
+
+import (
+	"context"
+	"io"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
+)
+
+// UploadRestoration stores an uploaded dump file as an execution of the
+// given backup, in the given destination or in the one of the backup when
+// it's not set, and starts its restoration in the background.
+//
+// The upload and the restoration are registered in the running tracker, so
+// a graceful shutdown waits for them to finish.
+//
+// Returns the execution created for the uploaded file.
+func (s *Service) UploadRestoration(
+	ctx context.Context, backupID uuid.UUID, destinationID uuid.NullUUID,
+	fileName string, reader io.Reader, databaseID uuid.NullUUID,
+	connString string,
+) (dbgen.Execution, error) {
+	if !s.running.Start() {
+		return dbgen.Execution{}, errShuttingDown
+	}
+
+	execution, err := s.executionsService.UploadExecution(
+		ctx, backupID, destinationID, fileName, reader,
+	)
+	if err != nil {
+		s.running.Done()
+		return dbgen.Execution{}, err
+	}
+
+	go func() {
+		defer s.running.Done()
+		_ = s.runRestoration(
+			context.Background(), execution.ID, databaseID, connString,
+		)
+	}()
+
+	return execution, nil
+}
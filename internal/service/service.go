@@ -1,15 +1,20 @@
 package service
 
 import (
+	"database/sql"
+
 	"github.com/eduardolat/pgbackweb/internal/config"
 	"github.com/eduardolat/pgbackweb/internal/cron"
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/integration"
 	"github.com/eduardolat/pgbackweb/internal/service/auth"
 	"github.com/eduardolat/pgbackweb/internal/service/backups"
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
+	"github.com/eduardolat/pgbackweb/internal/service/cluster"
 	"github.com/eduardolat/pgbackweb/internal/service/databases"
 	"github.com/eduardolat/pgbackweb/internal/service/destinations"
 	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/eduardolat/pgbackweb/internal/service/history"
 	"github.com/eduardolat/pgbackweb/internal/service/restorations"
 	"github.com/eduardolat/pgbackweb/internal/service/users"
 	"github.com/eduardolat/pgbackweb/internal/service/webhooks"
@@ -18,35 +23,49 @@ import (
 type Service struct {
 	AuthService         *auth.Service
 	BackupsService      *backups.Service
+	BlackoutsService    *blackouts.Service
+	ClusterService      *cluster.Service
 	DatabasesService    *databases.Service
 	DestinationsService *destinations.Service
 	ExecutionsService   *executions.Service
+	HistoryService      *history.Service
 	UsersService        *users.Service
 	RestorationsService *restorations.Service
 	WebhooksService     *webhooks.Service
 }
 
 func New(
-	env config.Env, dbgen *dbgen.Queries,
+	env config.Env, db *sql.DB, dbgen *dbgen.Queries,
 	cr *cron.Cron, ints *integration.Integration,
 ) *Service {
+	clusterService := cluster.New(env, db, dbgen)
 	webhooksService := webhooks.New(dbgen)
 	authService := auth.New(env, dbgen)
 	databasesService := databases.New(env, dbgen, ints, webhooksService)
 	destinationsService := destinations.New(env, dbgen, ints, webhooksService)
-	executionsService := executions.New(env, dbgen, ints, webhooksService)
+	blackoutsService := blackouts.New(dbgen)
+	executionsService := executions.New(
+		env, db, dbgen, ints, webhooksService, clusterService, blackoutsService,
+	)
 	usersService := users.New(dbgen)
-	backupsService := backups.New(dbgen, cr, executionsService)
+	historyService := history.New(env, dbgen)
+	backupsService := backups.New(
+		dbgen, cr, executionsService, clusterService,
+	)
 	restorationsService := restorations.New(
 		dbgen, ints, executionsService, databasesService, destinationsService,
+		clusterService,
 	)
 
 	return &Service{
 		AuthService:         authService,
 		BackupsService:      backupsService,
+		BlackoutsService:    blackoutsService,
+		ClusterService:      clusterService,
 		DatabasesService:    databasesService,
 		DestinationsService: destinationsService,
 		ExecutionsService:   executionsService,
+		HistoryService:      historyService,
 		UsersService:        usersService,
 		RestorationsService: restorationsService,
 		WebhooksService:     webhooksService,
@@ -58,6 +58,15 @@ func (s *Service) RunExecutionFailed(backupID uuid.UUID) {
 	}()
 }
 
+// RunStorageLowSpace runs the low space webhooks for the given destination
+// ID, or LocalBackupsTargetID for the legacy local backups.
+func (s *Service) RunStorageLowSpace(destinationID uuid.UUID) {
+	go func() {
+		ctx := context.Background()
+		runWebhook(s, ctx, EventTypeStorageLowSpace, destinationID)
+	}()
+}
+
 // runWebhook runs the webhooks for the given event type and target ID.
 func runWebhook(
 	s *Service, ctx context.Context, eventType eventType, targetID uuid.UUID,
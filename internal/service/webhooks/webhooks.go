@@ -2,9 +2,15 @@ package webhooks
 
 import (
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/google/uuid"
 	"github.com/orsinium-labs/enum"
 )
 
+// LocalBackupsTargetID is the target ID of the storage_low_space webhooks
+// for the legacy local backups, stored in the /backups directory without a
+// destination.
+var LocalBackupsTargetID = uuid.Nil
+
 type (
 	eventType     = enum.Member[eventTypeData]
 	eventTypeData struct {
@@ -34,6 +40,10 @@ var (
 	EventTypeExecutionFailed = eventType{
 		Value: eventTypeData{Key: "execution_failed", Name: "Execution failed"},
 	}
+
+	EventTypeStorageLowSpace = eventType{
+		Value: eventTypeData{Key: "storage_low_space", Name: "Storage low space"},
+	}
 )
 
 var FullEventTypes = map[string]string{
@@ -43,6 +53,7 @@ var FullEventTypes = map[string]string{
 	EventTypeDestinationUnhealthy.Value.Key: EventTypeDestinationUnhealthy.Value.Name,
 	EventTypeExecutionSuccess.Value.Key:     EventTypeExecutionSuccess.Value.Name,
 	EventTypeExecutionFailed.Value.Key:      EventTypeExecutionFailed.Value.Name,
+	EventTypeStorageLowSpace.Value.Key:      EventTypeStorageLowSpace.Value.Name,
 }
 
 type Service struct {
@@ -0,0 +1,54 @@
+package blackoututil
+
+import (
+	"time"
+
+	"github.com/adhocore/gronx"
+)
+
+// Window is a period of time in which the scheduled backups don't run.
+//
+// A one-off window goes from StartsAt to EndsAt. A recurring window starts at
+// every tick of CronExpression, evaluated in Location, and lasts Duration.
+type Window struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+
+	CronExpression string
+	Location       *time.Location
+	Duration       time.Duration
+}
+
+// IsRecurring returns true if the window repeats following a cron expression.
+func (w Window) IsRecurring() bool {
+	return w.CronExpression != ""
+}
+
+// ActiveAt returns whether the window is active at the given time and, if it
+// is, when the active occurrence of the window ends.
+func (w Window) ActiveAt(t time.Time) (bool, time.Time, error) {
+	if !w.IsRecurring() {
+		if !t.Before(w.StartsAt) && t.Before(w.EndsAt) {
+			return true, w.EndsAt, nil
+		}
+		return false, time.Time{}, nil
+	}
+
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// The latest occurrence is the one that ends last, so it is enough to
+	// check it even if the occurrences overlap
+	start, err := gronx.PrevTickBefore(w.CronExpression, t.In(loc), true)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	end := start.Add(w.Duration)
+	if t.Before(end) {
+		return true, end, nil
+	}
+	return false, time.Time{}, nil
+}
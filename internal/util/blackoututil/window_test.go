@@ -0,0 +1,80 @@
+package blackoututil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowActiveAt(t *testing.T) {
+	t.Run("One-off window", func(t *testing.T) {
+		w := Window{
+			StartsAt: time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC),
+			EndsAt:   time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		}
+
+		active, end, err := w.ActiveAt(time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.True(t, active)
+		assert.Equal(t, w.EndsAt, end)
+
+		active, _, err = w.ActiveAt(w.StartsAt)
+		assert.NoError(t, err)
+		assert.True(t, active)
+
+		active, _, err = w.ActiveAt(w.EndsAt)
+		assert.NoError(t, err)
+		assert.False(t, active)
+
+		active, _, err = w.ActiveAt(time.Date(2024, 1, 10, 7, 59, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("Recurring window", func(t *testing.T) {
+		// Every day from 22:00 to 02:00
+		w := Window{
+			CronExpression: "0 22 * * *",
+			Location:       time.UTC,
+			Duration:       4 * time.Hour,
+		}
+
+		active, end, err := w.ActiveAt(time.Date(2024, 1, 10, 23, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.True(t, active)
+		assert.True(t, time.Date(2024, 1, 11, 2, 0, 0, 0, time.UTC).Equal(end))
+
+		active, end, err = w.ActiveAt(time.Date(2024, 1, 11, 1, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.True(t, active)
+		assert.True(t, time.Date(2024, 1, 11, 2, 0, 0, 0, time.UTC).Equal(end))
+
+		active, _, err = w.ActiveAt(time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("Recurring window uses the location", func(t *testing.T) {
+		// Every day from 00:00 to 01:00 in UTC-3
+		w := Window{
+			CronExpression: "0 0 * * *",
+			Location:       time.FixedZone("UTC-3", -3*60*60),
+			Duration:       time.Hour,
+		}
+
+		active, _, err := w.ActiveAt(time.Date(2024, 1, 10, 3, 30, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.True(t, active)
+
+		active, _, err = w.ActiveAt(time.Date(2024, 1, 10, 0, 30, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("Invalid cron expression", func(t *testing.T) {
+		w := Window{CronExpression: "invalid", Duration: time.Hour}
+		_, _, err := w.ActiveAt(time.Now())
+		assert.Error(t, err)
+	})
+}
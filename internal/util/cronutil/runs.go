@@ -0,0 +1,72 @@
+package cronutil
+
+import (
+	"time"
+
+	"github.com/adhocore/gronx"
+)
+
+// RunsBetween returns the times, in the given location, at which a cron
+// expression is due after from (excluded) and until to (included), at most
+// limit times (the oldest ones). A limit of 0 or less means no limit.
+func RunsBetween(
+	expression string, loc *time.Location, from, to time.Time, limit int,
+) ([]time.Time, error) {
+	runs := []time.Time{}
+
+	ref := from.In(loc)
+	for limit <= 0 || len(runs) < limit {
+		next, err := gronx.NextTickAfter(expression, ref, false)
+		if err != nil {
+			return nil, err
+		}
+		if next.After(to) {
+			break
+		}
+
+		runs = append(runs, next)
+		ref = next
+	}
+
+	return runs, nil
+}
+
+// LastRunsBetween is like RunsBetween but it walks backwards from to, so it
+// returns at most limit times (the newest ones, sorted from oldest to newest)
+// without going through all the runs of the range. The limit must be greater
+// than 0.
+func LastRunsBetween(
+	expression string, loc *time.Location, from, to time.Time, limit int,
+) ([]time.Time, error) {
+	runs := []time.Time{}
+
+	ref := to.In(loc)
+	inclRef := true
+	for len(runs) < limit {
+		prev, err := gronx.PrevTickBefore(expression, ref, inclRef)
+		if err != nil {
+			return nil, err
+		}
+		if !prev.After(from) {
+			break
+		}
+
+		runs = append([]time.Time{prev}, runs...)
+		ref = prev
+		inclRef = false
+	}
+
+	return runs, nil
+}
+
+// NextRuns returns the next n times, in the given location, at which a cron
+// expression is due after from (excluded).
+func NextRuns(
+	expression string, loc *time.Location, from time.Time, n int,
+) ([]time.Time, error) {
+	if n <= 0 {
+		return []time.Time{}, nil
+	}
+
+	return RunsBetween(expression, loc, from, from.AddDate(100, 0, 0), n)
+}
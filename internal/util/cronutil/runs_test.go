@@ -0,0 +1,130 @@
+package cronutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsBetween(t *testing.T) {
+	from := time.Date(2024, 1, 1, 1, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 4, 1, 30, 0, 0, time.UTC)
+
+	t.Run("Returns the runs in the range", func(t *testing.T) {
+		runs, err := RunsBetween("0 2 * * *", time.UTC, from, to, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 3, 2, 0, 0, 0, time.UTC),
+		}, runs)
+	})
+
+	t.Run("Respects the limit", func(t *testing.T) {
+		runs, err := RunsBetween("0 2 * * *", time.UTC, from, to, 2)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 2)
+		assert.Equal(t, time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC), runs[1])
+	})
+
+	t.Run("Excludes from and includes to", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)
+		runs, err := RunsBetween("0 2 * * *", time.UTC, start, end, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{end}, runs)
+	})
+
+	t.Run("Uses the given location", func(t *testing.T) {
+		loc := time.FixedZone("UTC-3", -3*60*60)
+		runs, err := RunsBetween("0 2 * * *", loc, from, to, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC), runs[0].UTC())
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := RunsBetween("invalid", time.UTC, from, to, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestLastRunsBetween(t *testing.T) {
+	from := time.Date(2024, 1, 1, 1, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 4, 1, 30, 0, 0, time.UTC)
+
+	t.Run("Returns the newest runs in the range", func(t *testing.T) {
+		runs, err := LastRunsBetween("0 2 * * *", time.UTC, from, to, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 3, 2, 0, 0, 0, time.UTC),
+		}, runs)
+	})
+
+	t.Run("Matches RunsBetween under the limit", func(t *testing.T) {
+		last, err := LastRunsBetween("0 */6 * * *", time.UTC, from, to, 100)
+		assert.NoError(t, err)
+		all, err := RunsBetween("0 */6 * * *", time.UTC, from, to, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, len(all), len(last))
+		for i := range all {
+			assert.True(t, all[i].Equal(last[i]))
+		}
+	})
+
+	t.Run("Excludes from and includes to", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)
+		runs, err := LastRunsBetween("0 2 * * *", time.UTC, start, end, 10)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 1)
+		assert.True(t, end.Equal(runs[0]))
+	})
+
+	t.Run("Long ranges stop at the limit", func(t *testing.T) {
+		start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		runs, err := LastRunsBetween("* * * * *", time.UTC, start, to, 3)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 3)
+		assert.True(t, to.Add(-2*time.Minute).Equal(runs[0]))
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := LastRunsBetween("invalid", time.UTC, from, to, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestNextRuns(t *testing.T) {
+	from := time.Date(2024, 1, 1, 1, 30, 0, 0, time.UTC)
+
+	t.Run("Returns the next runs", func(t *testing.T) {
+		runs, err := NextRuns("0 */6 * * *", time.UTC, from, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+		}, runs)
+	})
+
+	t.Run("Uses the location", func(t *testing.T) {
+		loc := time.FixedZone("UTC-3", -3*60*60)
+		runs, err := NextRuns("0 0 * * *", loc, from, 1)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 1)
+		assert.True(t, time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC).Equal(runs[0]))
+	})
+
+	t.Run("Zero runs", func(t *testing.T) {
+		runs, err := NextRuns("0 0 * * *", time.UTC, from, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, runs)
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := NextRuns("invalid", time.UTC, from, 1)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,73 @@
+package drainutil
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker tracks the jobs running in the background so they can be drained
+// when the application shuts down.
+//
+// The zero value is ready to use.
+type Tracker struct {
+	mu       sync.Mutex
+	draining bool
+	running  int
+	idle     chan struct{}
+}
+
+// Start registers a new running job. It returns false if the tracker is
+// draining, in which case the job must not start and Done must not be called.
+func (t *Tracker) Start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false
+	}
+
+	t.running++
+	return true
+}
+
+// Done marks a job registered with Start as finished.
+func (t *Tracker) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.running--
+	if t.running == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// Running returns the number of running jobs.
+func (t *Tracker) Running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.running
+}
+
+// Drain stops accepting new jobs and waits until the running jobs finish or
+// the context is done, in which case it returns the context error.
+func (t *Tracker) Drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	if t.running == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
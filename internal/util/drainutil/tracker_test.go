@@ -0,0 +1,45 @@
+package drainutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	t.Run("Drain without running jobs returns right away", func(t *testing.T) {
+		var tracker Tracker
+		assert.NoError(t, tracker.Drain(context.Background()))
+		assert.False(t, tracker.Start())
+	})
+
+	t.Run("Drain waits for the running jobs", func(t *testing.T) {
+		var tracker Tracker
+		assert.True(t, tracker.Start())
+		assert.True(t, tracker.Start())
+		assert.Equal(t, 2, tracker.Running())
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			tracker.Done()
+			tracker.Done()
+		}()
+
+		assert.NoError(t, tracker.Drain(context.Background()))
+		assert.Equal(t, 0, tracker.Running())
+	})
+
+	t.Run("Drain stops at the context deadline", func(t *testing.T) {
+		var tracker Tracker
+		assert.True(t, tracker.Start())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, tracker.Drain(ctx), context.DeadlineExceeded)
+		assert.False(t, tracker.Start())
+		assert.Equal(t, 1, tracker.Running())
+	})
+}
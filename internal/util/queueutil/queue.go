@@ -0,0 +1,54 @@
+package queueutil
+
+// Item is a queued or running execution.
+type Item struct {
+	// Server identifies the database server of the execution, see ServerKey.
+	Server string
+	// Destination identifies the destination of the execution.
+	Destination string
+	// DestinationLimit is the maximum number of running executions of the
+	// destination, 0 means no limit.
+	DestinationLimit int
+}
+
+// Limits are the concurrency limits of the queue, 0 means no limit.
+type Limits struct {
+	Global    int
+	PerServer int
+}
+
+// Pick returns the indexes of the queued items (sorted by priority) that can
+// start without exceeding the limits, given the items already running.
+//
+// Items blocked by a server or destination limit don't block the items
+// behind them that are not affected by that limit.
+func Pick(running []Item, queued []Item, limits Limits) []int {
+	total := len(running)
+	byServer := map[string]int{}
+	byDestination := map[string]int{}
+	for _, item := range running {
+		byServer[item.Server]++
+		byDestination[item.Destination]++
+	}
+
+	picked := []int{}
+	for i, item := range queued {
+		if limits.Global > 0 && total >= limits.Global {
+			break
+		}
+		if limits.PerServer > 0 && byServer[item.Server] >= limits.PerServer {
+			continue
+		}
+		if item.DestinationLimit > 0 &&
+			byDestination[item.Destination] >= item.DestinationLimit {
+			continue
+		}
+
+		picked = append(picked, i)
+		total++
+		byServer[item.Server]++
+		byDestination[item.Destination]++
+	}
+
+	return picked
+}
@@ -0,0 +1,51 @@
+package queueutil
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ServerKey returns the host and port of the database server of a
+// PostgreSQL connection string, in URL (postgres://...) or key/value
+// (host=... port=...) format. Executions of databases in the same server
+// share the same key.
+//
+// If the connection string can't be parsed, it is returned as is so every
+// database is treated as a different server.
+func ServerKey(connString string) string {
+	connString = strings.TrimSpace(connString)
+	host, port := "", ""
+
+	if strings.HasPrefix(connString, "postgres://") ||
+		strings.HasPrefix(connString, "postgresql://") {
+		u, err := url.Parse(connString)
+		if err != nil {
+			return connString
+		}
+		host, port = u.Hostname(), u.Port()
+	} else {
+		for _, field := range strings.Fields(connString) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, "'")
+
+			switch key {
+			case "host":
+				host = value
+			case "port":
+				port = value
+			}
+		}
+	}
+
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "5432"
+	}
+
+	return strings.ToLower(host) + ":" + port
+}
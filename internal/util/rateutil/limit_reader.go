@@ -0,0 +1,45 @@
+package rateutil
+
+import (
+	"io"
+	"time"
+)
+
+// LimitReader returns a reader that reads from r at most bytesPerSecond bytes
+// per second on average. If bytesPerSecond is 0 or negative r is returned
+// unchanged.
+func LimitReader(r io.Reader, bytesPerSecond int64) io.Reader {
+	if bytesPerSecond <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, rate: bytesPerSecond}
+}
+
+type limitedReader struct {
+	r     io.Reader
+	rate  int64
+	start time.Time
+	total int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.start.IsZero() {
+		l.start = time.Now()
+	}
+
+	// Never read more than one second worth of data at once, so the pauses
+	// between reads stay short
+	if int64(len(p)) > l.rate {
+		p = p[:l.rate]
+	}
+
+	n, err := l.r.Read(p)
+	l.total += int64(n)
+
+	expected := time.Duration(float64(l.total) / float64(l.rate) * float64(time.Second))
+	if wait := expected - time.Since(l.start); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	return n, err
+}
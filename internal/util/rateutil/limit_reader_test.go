@@ -0,0 +1,38 @@
+package rateutil
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitReader(t *testing.T) {
+	t.Run("Unlimited returns the same reader", func(t *testing.T) {
+		r := bytes.NewReader([]byte("hello"))
+		assert.Equal(t, io.Reader(r), LimitReader(r, 0))
+		assert.Equal(t, io.Reader(r), LimitReader(r, -1))
+	})
+
+	t.Run("Reads all the data", func(t *testing.T) {
+		data := bytes.Repeat([]byte("a"), 1000)
+		got, err := io.ReadAll(LimitReader(bytes.NewReader(data), 1_000_000))
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("Limits the read rate", func(t *testing.T) {
+		data := bytes.Repeat([]byte("a"), 300)
+
+		start := time.Now()
+		got, err := io.ReadAll(LimitReader(bytes.NewReader(data), 1000))
+		elapsed := time.Since(start)
+
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+		assert.GreaterOrEqual(t, elapsed, 250*time.Millisecond)
+		assert.Less(t, elapsed, 2*time.Second)
+	})
+}
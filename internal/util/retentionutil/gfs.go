@@ -0,0 +1,79 @@
+package retentionutil
+
+import (
+	"fmt"
+	"time"
+)
+
+// GFSPolicy is a grandfather-father-son retention policy. Each field is the
+// number of periods (days, weeks, months or years) for which the newest item
+// of the period is kept. A value of 0 disables that period.
+type GFSPolicy struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+	Yearly  int
+}
+
+// Enabled returns true if any of the periods of the policy is enabled.
+func (p GFSPolicy) Enabled() bool {
+	return p.Daily > 0 || p.Weekly > 0 || p.Monthly > 0 || p.Yearly > 0
+}
+
+// GFSKeepers returns, for every item that must be kept by the policy, the
+// periods that keep it ("daily", "weekly", "monthly" and/or "yearly").
+//
+// times must be sorted from newest to oldest, the returned map is keyed by
+// the index of the item in times. Periods are calculated in the given
+// location, weeks are ISO 8601 weeks.
+func GFSKeepers(
+	times []time.Time, policy GFSPolicy, loc *time.Location,
+) map[int][]string {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	periods := []struct {
+		name  string
+		count int
+		key   func(t time.Time) string
+	}{
+		{"daily", policy.Daily, func(t time.Time) string {
+			return t.Format("2006-01-02")
+		}},
+		{"weekly", policy.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{"monthly", policy.Monthly, func(t time.Time) string {
+			return t.Format("2006-01")
+		}},
+		{"yearly", policy.Yearly, func(t time.Time) string {
+			return t.Format("2006")
+		}},
+	}
+
+	keepers := map[int][]string{}
+	for _, period := range periods {
+		if period.count <= 0 {
+			continue
+		}
+
+		seen := map[string]bool{}
+		for i, t := range times {
+			if len(seen) >= period.count {
+				break
+			}
+
+			key := period.key(t.In(loc))
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			keepers[i] = append(keepers[i], period.name)
+		}
+	}
+
+	return keepers
+}
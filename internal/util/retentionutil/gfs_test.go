@@ -0,0 +1,98 @@
+package retentionutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGFSPolicyEnabled(t *testing.T) {
+	assert.False(t, GFSPolicy{}.Enabled())
+	assert.True(t, GFSPolicy{Daily: 1}.Enabled())
+	assert.True(t, GFSPolicy{Yearly: 1}.Enabled())
+}
+
+func TestGFSKeepers(t *testing.T) {
+	date := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	times := []time.Time{
+		date("2024-03-05 18:00"), // 0 Tuesday
+		date("2024-03-05 06:00"), // 1 same day
+		date("2024-03-04 06:00"), // 2 Monday, same week
+		date("2024-03-03 06:00"), // 3 Sunday, previous week
+		date("2024-02-20 06:00"), // 4 previous month
+		date("2023-12-31 06:00"), // 5 previous year
+		date("2023-06-01 06:00"), // 6
+	}
+
+	tests := []struct {
+		name   string
+		policy GFSPolicy
+		want   map[int][]string
+	}{
+		{
+			name:   "Disabled policy keeps nothing",
+			policy: GFSPolicy{},
+			want:   map[int][]string{},
+		},
+		{
+			name:   "Daily keeps the newest of each day",
+			policy: GFSPolicy{Daily: 3},
+			want: map[int][]string{
+				0: {"daily"},
+				2: {"daily"},
+				3: {"daily"},
+			},
+		},
+		{
+			name:   "Weekly uses ISO weeks",
+			policy: GFSPolicy{Weekly: 2},
+			want: map[int][]string{
+				0: {"weekly"},
+				3: {"weekly"},
+			},
+		},
+		{
+			name:   "Monthly and yearly",
+			policy: GFSPolicy{Monthly: 3, Yearly: 2},
+			want: map[int][]string{
+				0: {"monthly", "yearly"},
+				4: {"monthly"},
+				5: {"monthly", "yearly"},
+			},
+		},
+		{
+			name:   "Periods with more slots than items keep all of them",
+			policy: GFSPolicy{Yearly: 10},
+			want: map[int][]string{
+				0: {"yearly"},
+				5: {"yearly"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GFSKeepers(times, tt.policy, time.UTC)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGFSKeepersLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	times := []time.Time{
+		time.Date(2024, 3, 5, 3, 0, 0, 0, time.UTC), // 2024-03-04 22:00 local
+		time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+	}
+
+	got := GFSKeepers(times, GFSPolicy{Daily: 2}, loc)
+	assert.Equal(t, map[int][]string{0: {"daily"}}, got)
+}
@@ -19,4 +19,16 @@ func MountRouter(
 		servs: servs,
 	}
 	v1.GET("/health", h.healthHandler)
+	v1.POST(
+		"/restorations/upload", h.uploadRestorationHandler,
+		mids.InjectReqctx, mids.RequireAuth,
+	)
+	v1.GET(
+		"/executions/expirations", h.upcomingExpirationsHandler,
+		mids.InjectReqctx, mids.RequireAuth,
+	)
+	v1.GET(
+		"/backups/schedule", h.upcomingRunsHandler,
+		mids.InjectReqctx, mids.RequireAuth,
+	)
 }
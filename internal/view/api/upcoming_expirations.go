@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/labstack/echo/v4"
+)
+
+// upcomingExpirationsHandler returns the executions that will be deleted by
+// the retention rules of their backups over the next days (30 by default).
+func (h *handlers) upcomingExpirationsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var queryData struct {
+		Days int `query:"days" validate:"omitempty,min=1,max=365"`
+	}
+	if err := c.Bind(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := validate.Struct(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if queryData.Days == 0 {
+		queryData.Days = 30
+	}
+
+	expirations, err := h.servs.ExecutionsService.GetUpcomingExpirations(
+		ctx, queryData.Days,
+	)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"days":        queryData.Days,
+		"expirations": expirations,
+	})
+}
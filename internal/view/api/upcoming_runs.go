@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/labstack/echo/v4"
+)
+
+// upcomingRunsHandler returns the runs of the active backups scheduled over
+// the next days (7 by default).
+func (h *handlers) upcomingRunsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var queryData struct {
+		Days int `query:"days" validate:"omitempty,min=1,max=31"`
+	}
+	if err := c.Bind(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := validate.Struct(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if queryData.Days == 0 {
+		queryData.Days = 7
+	}
+
+	schedule, err := h.servs.BackupsService.GetUpcomingRuns(
+		ctx, queryData.Days,
+	)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// uploadRestorationHandler stores an uploaded dump file as an execution of
+// the given backup and starts its restoration.
+//
+// It expects a multipart form with the file, the backup_id and either a
+// database_id or a conn_string. The optional destination_id stores the file
+// in that destination instead of the one of the backup.
+func (h *handlers) uploadRestorationHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var formData struct {
+		BackupID      uuid.UUID `form:"backup_id" validate:"required,uuid"`
+		DestinationID uuid.UUID `form:"destination_id" validate:"omitempty,uuid"`
+		DatabaseID    uuid.UUID `form:"database_id" validate:"omitempty,uuid"`
+		ConnString    string    `form:"conn_string" validate:"omitempty"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	if (formData.DatabaseID == uuid.Nil) == (formData.ConnString == "") {
+		return c.String(
+			http.StatusBadRequest,
+			"exactly one of database_id or conn_string is required",
+		)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "file is required")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	defer file.Close()
+
+	execution, err := h.servs.RestorationsService.UploadRestoration(
+		ctx, formData.BackupID,
+		uuid.NullUUID{
+			Valid: formData.DestinationID != uuid.Nil,
+			UUID:  formData.DestinationID,
+		},
+		fileHeader.Filename, file,
+		uuid.NullUUID{
+			Valid: formData.DatabaseID != uuid.Nil,
+			UUID:  formData.DatabaseID,
+		},
+		formData.ConnString,
+	)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]any{
+		"execution_id": execution.ID.String(),
+		"file_size":    execution.FileSize.Int64,
+	})
+}
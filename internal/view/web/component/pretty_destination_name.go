@@ -11,18 +11,21 @@ func PrettyDestinationName(
 	isLocal bool, destinationName sql.NullString,
 ) nodx.Node {
 	icon := lucide.Cloud
-	if !destinationName.Valid {
+	if isLocal {
+		icon = lucide.HardDrive
+	}
+
+	if isLocal && !destinationName.Valid {
 		destinationName = sql.NullString{
 			Valid:  true,
-			String: "Unknown destination",
+			String: "Local",
 		}
 	}
 
-	if isLocal {
-		icon = lucide.HardDrive
+	if !destinationName.Valid {
 		destinationName = sql.NullString{
 			Valid:  true,
-			String: "Local",
+			String: "Unknown destination",
 		}
 	}
 
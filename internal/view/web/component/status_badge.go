@@ -7,12 +7,16 @@ import (
 func StatusBadge(status string) nodx.Node {
 	class := ""
 	switch status {
+	case "queued":
+		class = "badge-ghost"
 	case "running":
 		class = "badge-info"
 	case "success":
 		class = "badge-success"
 	case "failed":
 		class = "badge-error"
+	case "skipped":
+		class = "badge-neutral badge-outline"
 	case "deleted":
 		class = "badge-warning"
 	default:
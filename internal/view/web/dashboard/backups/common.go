@@ -1,10 +1,16 @@
 package backups
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/service/backups"
 	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/google/uuid"
 	nodx "github.com/nodxdev/nodxgo"
+	alpine "github.com/nodxdev/nodxgo-alpine"
 	lucide "github.com/nodxdev/nodxgo-lucide"
 )
 
@@ -21,14 +27,23 @@ func localBackupsHelp() []nodx.Node {
 			nodx.Class("mt-2"),
 			component.H3Text("Remote backups"),
 			component.PText(`
-				Remote backups are stored in a destination. A destination is a remote
-				S3 compatible storage. With this option you don't need to worry about
-				creating and managing docker volumes.
+				Remote backups are stored in a destination. A destination can be a
+				remote S3 compatible storage or a local path in the server, so you can
+				mount multiple volumes and create one local destination for each one.
 			`),
 		),
 	}
 }
 
+// prettyDestinationOption returns the label of a destination to be used in
+// a select option.
+func prettyDestinationOption(name string, isLocal bool) string {
+	if isLocal {
+		return name + " (local)"
+	}
+	return name
+}
+
 func cronExpressionHelp() []nodx.Node {
 	return []nodx.Node{
 		component.PText(`
@@ -149,10 +164,285 @@ func retentionDaysHelp() []nodx.Node {
 			component.PText(`
 				If you set the retention days to 0, the backups will never be deleted.
 			`),
+
+			component.PText(`
+				When the keep last option or a GFS retention policy is configured, the
+				backups kept by them are never deleted by the retention days, and the
+				rest of the backups are deleted once they are older than the retention
+				days (or right away if the retention days are 0).
+			`),
+
+			component.PText(`
+				The newest successful backup is never deleted, even if it is older than
+				the retention days, so you always have at least one backup to restore.
+			`),
+		),
+	}
+}
+
+const (
+	triggerCron  = "cron"
+	triggerChain = "chain"
+)
+
+// triggerOf returns the trigger of a backup to initialize the trigger select.
+func triggerOf(runAfterBackupID uuid.NullUUID) string {
+	if runAfterBackupID.Valid {
+		return triggerChain
+	}
+	return triggerCron
+}
+
+// parseRunAfter returns the backup to run after from the form values, it is
+// only set when the backup is triggered by another backup.
+func parseRunAfter(trigger string, runAfterBackupID string) (uuid.NullUUID, error) {
+	if trigger != triggerChain {
+		return uuid.NullUUID{}, nil
+	}
+
+	id, err := uuid.Parse(runAfterBackupID)
+	if err != nil {
+		return uuid.NullUUID{}, fmt.Errorf("select the backup to run after")
+	}
+
+	return uuid.NullUUID{Valid: true, UUID: id}, nil
+}
+
+// triggerSelect renders the select to choose between a cron schedule and
+// running the backup after another one, it needs a "trigger" alpine variable.
+// cronExpressionHelpText is the help text of the cron expression input, it
+// is required for chained backups too.
+const cronExpressionHelpText = "The cron expression to schedule the backup, chained backups use it only if they stop running after another backup"
+
+func triggerSelect() nodx.Node {
+	return component.SelectControl(component.SelectControlParams{
+		Name:     "trigger",
+		Label:    "Trigger",
+		Required: true,
+		Children: []nodx.Node{
+			alpine.XModel("trigger"),
+			nodx.Option(nodx.Value(triggerCron), nodx.Text("Cron schedule")),
+			nodx.Option(nodx.Value(triggerChain), nodx.Text("After another backup")),
+		},
+		HelpButtonChildren: triggerHelp(),
+	})
+}
+
+func triggerHelp() []nodx.Node {
+	return []nodx.Node{
+		nodx.Div(
+			nodx.Class("space-y-2"),
+
+			component.PText(`
+				A backup can run on its cron schedule or right after another backup
+				finishes, for example to back up several databases one after the
+				other without guessing how long each one takes.
+			`),
+
+			component.PText(`
+				Chained backups respect the blackout windows of their database and
+				don't run if the backup they run after is skipped or interrupted.
+			`),
+
+			component.PText(`
+				A backup can't be deleted while other backups run after it, change
+				their trigger first.
+			`),
 		),
 	}
 }
 
+// runAfterControls renders the backup to run after and the condition to run
+// it, shown when the trigger is "chain". The backup being edited, if any, is
+// excluded from the options.
+func runAfterControls(
+	allBackups []dbgen.Backup, backupID uuid.UUID,
+	runAfterBackupID uuid.NullUUID, condition string,
+) nodx.Node {
+	conditionOption := func(value, label string) nodx.Node {
+		return nodx.Option(
+			nodx.Value(value),
+			nodx.Text(label),
+			nodx.If(value == condition, nodx.Selected("")),
+		)
+	}
+
+	backupOptions := []nodx.Node{}
+	for _, backup := range allBackups {
+		if backup.ID == backupID {
+			continue
+		}
+
+		backupOptions = append(backupOptions, nodx.Option(
+			nodx.Value(backup.ID.String()),
+			nodx.Text(backup.Name),
+			nodx.If(
+				runAfterBackupID.Valid && backup.ID == runAfterBackupID.UUID,
+				nodx.Selected(""),
+			),
+		))
+	}
+
+	return alpine.Template(
+		alpine.XIf("trigger == 'chain'"),
+		nodx.Div(
+			nodx.Class("space-y-2"),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:        "run_after_backup_id",
+				Label:       "Run after",
+				Required:    true,
+				Placeholder: "Select a backup",
+				Children:    backupOptions,
+			}),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:     "run_after_condition",
+				Label:    "Run when",
+				Required: true,
+				Children: []nodx.Node{
+					conditionOption(backups.RunAfterConditionSuccess, "It succeeds"),
+					conditionOption(backups.RunAfterConditionAlways, "It finishes, even if it fails"),
+				},
+			}),
+		),
+	)
+}
+
+// runAfterConditionLabel returns a short description of when a chained
+// backup runs, used in the backups list.
+func runAfterConditionLabel(condition string) string {
+	if condition == backups.RunAfterConditionAlways {
+		return "Always"
+	}
+	return "On success"
+}
+
+func catchUpPolicySelect(value string) nodx.Node {
+	option := func(policy, label string) nodx.Node {
+		return nodx.Option(
+			nodx.Value(policy),
+			nodx.Text(label),
+			nodx.If(policy == value, nodx.Selected("")),
+		)
+	}
+
+	return component.SelectControl(component.SelectControlParams{
+		Name:     "catch_up_policy",
+		Label:    "Missed runs",
+		Required: true,
+		HelpText: "What to do with the runs missed while PG Back Web was down",
+		Children: []nodx.Node{
+			option(backups.CatchUpPolicySkip, "Skip missed runs"),
+			option(backups.CatchUpPolicyOnce, "Run once"),
+			option(backups.CatchUpPolicyAll, "Run all missed runs"),
+		},
+		HelpButtonChildren: catchUpPolicyHelp(),
+	})
+}
+
+func catchUpPolicyHelp() []nodx.Node {
+	return []nodx.Node{
+		nodx.Div(
+			nodx.Class("space-y-2"),
+
+			component.PText(`
+				When PG Back Web starts, it compares the last execution of the backup
+				with its cron expression to find the runs missed while it was down.
+			`),
+
+			component.PText(`
+				Skip ignores the missed runs and waits for the next scheduled run.
+				Run once queues a single execution if at least one run was missed.
+				Run all queues one execution for each missed run, up to the 50 most
+				recent ones.
+			`),
+		),
+	}
+}
+
+func keepLastInput(value int16) nodx.Node {
+	return component.InputControl(component.InputControlParams{
+		Name:        "keep_last",
+		Label:       "Keep last successful backups",
+		Placeholder: "0",
+		Type:        component.InputTypeNumber,
+		Pattern:     "[0-9]+",
+		HelpText:    "Number of newest successful backups that are always kept, use 0 to disable",
+		Children: []nodx.Node{
+			nodx.Min("0"),
+			nodx.Max("10000"),
+			nodx.Value(strconv.Itoa(int(value))),
+		},
+	})
+}
+
+// gfsRetentionValues are the current values of the GFS retention policy of a
+// backup, used to fill the create and edit forms.
+type gfsRetentionValues struct {
+	KeepDaily   int16
+	KeepWeekly  int16
+	KeepMonthly int16
+	KeepYearly  int16
+}
+
+func gfsRetentionHelp() []nodx.Node {
+	return []nodx.Node{
+		nodx.Div(
+			nodx.Class("space-y-2"),
+
+			component.PText(`
+				Grandfather-father-son (GFS) retention keeps the newest successful
+				backup of each of the last N days, weeks, months and years. For example
+				7 daily, 4 weekly and 12 monthly keeps a backup of each of the last 7
+				days, 4 weeks and 12 months.
+			`),
+
+			component.PText(`
+				Days, weeks (starting on monday), months and years are evaluated in the
+				time zone of the backup. Leave all of them at 0 to disable GFS
+				retention and only use the retention days.
+			`),
+		),
+	}
+}
+
+func gfsRetentionOptions(values gfsRetentionValues) nodx.Node {
+	input := func(name, label string, value int16) nodx.Node {
+		return component.InputControl(component.InputControlParams{
+			Name:        name,
+			Label:       label,
+			Placeholder: "0",
+			Type:        component.InputTypeNumber,
+			Children: []nodx.Node{
+				nodx.Min("0"),
+				nodx.Max("1000"),
+				nodx.Value(strconv.Itoa(int(value))),
+			},
+		})
+	}
+
+	return nodx.Div(
+		nodx.Class("pt-2"),
+		nodx.Div(
+			nodx.Class("flex justify-start items-center space-x-1"),
+			component.H2Text("GFS retention"),
+			component.HelpButtonModal(component.HelpButtonModalParams{
+				ModalTitle: "GFS retention",
+				Children:   gfsRetentionHelp(),
+			}),
+		),
+
+		nodx.Div(
+			nodx.Class("mt-2 grid grid-cols-4 gap-2"),
+			input("keep_daily", "Daily", values.KeepDaily),
+			input("keep_weekly", "Weekly", values.KeepWeekly),
+			input("keep_monthly", "Monthly", values.KeepMonthly),
+			input("keep_yearly", "Yearly", values.KeepYearly),
+		),
+	)
+}
+
 func pgDumpOptionsHelp() []nodx.Node {
 	return []nodx.Node{
 		nodx.Div(
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/service/backups"
 	"github.com/eduardolat/pgbackweb/internal/staticdata"
 	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
 	"github.com/eduardolat/pgbackweb/internal/validate"
@@ -26,17 +27,29 @@ func (h *handlers) createBackupHandler(c echo.Context) error {
 		DestinationID  uuid.UUID `form:"destination_id" validate:"omitempty,uuid"`
 		IsLocal        string    `form:"is_local" validate:"required,oneof=true false"`
 		Name           string    `form:"name" validate:"required"`
-		CronExpression string    `form:"cron_expression" validate:"required"`
+		Trigger        string    `form:"trigger" validate:"required,oneof=cron chain"`
+		CronExpression string    `form:"cron_expression"`
 		TimeZone       string    `form:"time_zone" validate:"required"`
 		IsActive       string    `form:"is_active" validate:"required,oneof=true false"`
 		DestDir        string    `form:"dest_dir" validate:"required"`
 		RetentionDays  int16     `form:"retention_days"`
-		OptDataOnly    string    `form:"opt_data_only" validate:"required,oneof=true false"`
-		OptSchemaOnly  string    `form:"opt_schema_only" validate:"required,oneof=true false"`
-		OptClean       string    `form:"opt_clean" validate:"required,oneof=true false"`
-		OptIfExists    string    `form:"opt_if_exists" validate:"required,oneof=true false"`
-		OptCreate      string    `form:"opt_create" validate:"required,oneof=true false"`
-		OptNoComments  string    `form:"opt_no_comments" validate:"required,oneof=true false"`
+		DumpRateLimit  int32     `form:"dump_rate_limit" validate:"min=0"`
+		KeepDaily      int16     `form:"keep_daily" validate:"min=0"`
+		KeepWeekly     int16     `form:"keep_weekly" validate:"min=0"`
+		KeepMonthly    int16     `form:"keep_monthly" validate:"min=0"`
+		KeepYearly     int16     `form:"keep_yearly" validate:"min=0"`
+		KeepLast       int16     `form:"keep_last" validate:"min=0"`
+		CatchUpPolicy  string    `form:"catch_up_policy" validate:"required,oneof=skip once all"`
+
+		RunAfterBackupID  string `form:"run_after_backup_id"`
+		RunAfterCondition string `form:"run_after_condition" validate:"omitempty,oneof=success always"`
+
+		OptDataOnly   string `form:"opt_data_only" validate:"required,oneof=true false"`
+		OptSchemaOnly string `form:"opt_schema_only" validate:"required,oneof=true false"`
+		OptClean      string `form:"opt_clean" validate:"required,oneof=true false"`
+		OptIfExists   string `form:"opt_if_exists" validate:"required,oneof=true false"`
+		OptCreate     string `form:"opt_create" validate:"required,oneof=true false"`
+		OptNoComments string `form:"opt_no_comments" validate:"required,oneof=true false"`
 	}
 	if err := c.Bind(&formData); err != nil {
 		return respondhtmx.ToastError(c, err.Error())
@@ -45,7 +58,17 @@ func (h *handlers) createBackupHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
-	_, err := h.servs.BackupsService.CreateBackup(
+	runAfterBackupID, err := parseRunAfter(
+		formData.Trigger, formData.RunAfterBackupID,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if formData.RunAfterCondition == "" {
+		formData.RunAfterCondition = backups.RunAfterConditionSuccess
+	}
+
+	_, err = h.servs.BackupsService.CreateBackup(
 		ctx, dbgen.BackupsServiceCreateBackupParams{
 			DatabaseID: formData.DatabaseID,
 			DestinationID: uuid.NullUUID{
@@ -58,12 +81,22 @@ func (h *handlers) createBackupHandler(c echo.Context) error {
 			IsActive:       formData.IsActive == "true",
 			DestDir:        formData.DestDir,
 			RetentionDays:  formData.RetentionDays,
+			DumpRateLimit:  formData.DumpRateLimit,
+			KeepDaily:      formData.KeepDaily,
+			KeepWeekly:     formData.KeepWeekly,
+			KeepMonthly:    formData.KeepMonthly,
+			KeepYearly:     formData.KeepYearly,
+			KeepLast:       formData.KeepLast,
+			CatchUpPolicy:  formData.CatchUpPolicy,
 			OptDataOnly:    formData.OptDataOnly == "true",
 			OptSchemaOnly:  formData.OptSchemaOnly == "true",
 			OptClean:       formData.OptClean == "true",
 			OptIfExists:    formData.OptIfExists == "true",
 			OptCreate:      formData.OptCreate == "true",
 			OptNoComments:  formData.OptNoComments == "true",
+
+			RunAfterBackupID:  runAfterBackupID,
+			RunAfterCondition: formData.RunAfterCondition,
 		},
 	)
 	if err != nil {
@@ -86,14 +119,20 @@ func (h *handlers) createBackupFormHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
+	allBackups, err := h.servs.BackupsService.GetAllBackups(ctx)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
 	return echoutil.RenderNodx(
-		c, http.StatusOK, createBackupForm(databases, destinations),
+		c, http.StatusOK, createBackupForm(databases, destinations, allBackups),
 	)
 }
 
 func createBackupForm(
 	databases []dbgen.DatabasesServiceGetAllDatabasesRow,
 	destinations []dbgen.DestinationsServiceGetAllDestinationsRow,
+	allBackups []dbgen.Backup,
 ) nodx.Node {
 	yesNoOptions := func() nodx.Node {
 		return nodx.Group(
@@ -111,6 +150,7 @@ func createBackupForm(
 
 		alpine.XData(`{
 			is_local: "false",
+			trigger: "cron",
 		}`),
 
 		component.InputControl(component.InputControlParams{
@@ -159,24 +199,33 @@ func createBackupForm(
 					nodx.Map(
 						destinations,
 						func(dest dbgen.DestinationsServiceGetAllDestinationsRow) nodx.Node {
-							return nodx.Option(nodx.Value(dest.ID.String()), nodx.Text(dest.Name))
+							return nodx.Option(
+								nodx.Value(dest.ID.String()),
+								nodx.Text(prettyDestinationOption(dest.Name, dest.IsLocal)),
+							)
 						},
 					),
 				},
 			}),
 		),
 
+		triggerSelect(),
+
 		component.InputControl(component.InputControlParams{
 			Name:               "cron_expression",
 			Label:              "Cron expression",
 			Placeholder:        "* * * * *",
 			Required:           true,
 			Type:               component.InputTypeText,
-			HelpText:           "The cron expression to schedule the backup",
+			HelpText:           cronExpressionHelpText,
 			Pattern:            `^\S+\s+\S+\s+\S+\s+\S+\s+\S+$`,
 			HelpButtonChildren: cronExpressionHelp(),
 		}),
 
+		runAfterControls(
+			allBackups, uuid.Nil, uuid.NullUUID{}, backups.RunAfterConditionSuccess,
+		),
+
 		component.SelectControl(component.SelectControlParams{
 			Name:        "time_zone",
 			Label:       "Time zone",
@@ -198,6 +247,8 @@ func createBackupForm(
 			HelpButtonChildren: timezoneFilenamesHelp(),
 		}),
 
+		catchUpPolicySelect(backups.CatchUpPolicySkip),
+
 		component.InputControl(component.InputControlParams{
 			Name:               "dest_dir",
 			Label:              "Destination directory",
@@ -223,6 +274,23 @@ func createBackupForm(
 			},
 		}),
 
+		keepLastInput(0),
+
+		gfsRetentionOptions(gfsRetentionValues{}),
+
+		component.InputControl(component.InputControlParams{
+			Name:        "dump_rate_limit",
+			Label:       "pg_dump read limit (KiB/s)",
+			Placeholder: "0",
+			Type:        component.InputTypeNumber,
+			Pattern:     "[0-9]+",
+			HelpText:    "Maximum speed, in KiB per second, at which the pg_dump output is read, use 0 for no limit",
+			Children: []nodx.Node{
+				nodx.Min("0"),
+				nodx.Value("0"),
+			},
+		}),
+
 		component.SelectControl(component.SelectControlParams{
 			Name:     "is_active",
 			Label:    "Activate backup",
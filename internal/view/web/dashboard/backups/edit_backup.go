@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	nodx "github.com/nodxdev/nodxgo"
+	alpine "github.com/nodxdev/nodxgo-alpine"
 	htmx "github.com/nodxdev/nodxgo-htmx"
 	lucide "github.com/nodxdev/nodxgo-lucide"
 )
@@ -26,17 +27,29 @@ func (h *handlers) editBackupHandler(c echo.Context) error {
 
 	var formData struct {
 		Name           string `form:"name" validate:"required"`
-		CronExpression string `form:"cron_expression" validate:"required"`
+		Trigger        string `form:"trigger" validate:"required,oneof=cron chain"`
+		CronExpression string `form:"cron_expression"`
 		TimeZone       string `form:"time_zone" validate:"required"`
 		IsActive       string `form:"is_active" validate:"required,oneof=true false"`
 		DestDir        string `form:"dest_dir" validate:"required"`
 		RetentionDays  int16  `form:"retention_days"`
-		OptDataOnly    string `form:"opt_data_only" validate:"required,oneof=true false"`
-		OptSchemaOnly  string `form:"opt_schema_only" validate:"required,oneof=true false"`
-		OptClean       string `form:"opt_clean" validate:"required,oneof=true false"`
-		OptIfExists    string `form:"opt_if_exists" validate:"required,oneof=true false"`
-		OptCreate      string `form:"opt_create" validate:"required,oneof=true false"`
-		OptNoComments  string `form:"opt_no_comments" validate:"required,oneof=true false"`
+		DumpRateLimit  int32  `form:"dump_rate_limit" validate:"min=0"`
+		KeepDaily      int16  `form:"keep_daily" validate:"min=0"`
+		KeepWeekly     int16  `form:"keep_weekly" validate:"min=0"`
+		KeepMonthly    int16  `form:"keep_monthly" validate:"min=0"`
+		KeepYearly     int16  `form:"keep_yearly" validate:"min=0"`
+		KeepLast       int16  `form:"keep_last" validate:"min=0"`
+		CatchUpPolicy  string `form:"catch_up_policy" validate:"required,oneof=skip once all"`
+
+		RunAfterBackupID  string `form:"run_after_backup_id"`
+		RunAfterCondition string `form:"run_after_condition" validate:"omitempty,oneof=success always"`
+
+		OptDataOnly   string `form:"opt_data_only" validate:"required,oneof=true false"`
+		OptSchemaOnly string `form:"opt_schema_only" validate:"required,oneof=true false"`
+		OptClean      string `form:"opt_clean" validate:"required,oneof=true false"`
+		OptIfExists   string `form:"opt_if_exists" validate:"required,oneof=true false"`
+		OptCreate     string `form:"opt_create" validate:"required,oneof=true false"`
+		OptNoComments string `form:"opt_no_comments" validate:"required,oneof=true false"`
 	}
 	if err := c.Bind(&formData); err != nil {
 		return respondhtmx.ToastError(c, err.Error())
@@ -45,6 +58,13 @@ func (h *handlers) editBackupHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
+	runAfterBackupID, err := parseRunAfter(
+		formData.Trigger, formData.RunAfterBackupID,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
 	_, err = h.servs.BackupsService.UpdateBackup(
 		ctx, dbgen.BackupsServiceUpdateBackupParams{
 			ID:             backupID,
@@ -54,12 +74,26 @@ func (h *handlers) editBackupHandler(c echo.Context) error {
 			IsActive:       sql.NullBool{Bool: formData.IsActive == "true", Valid: true},
 			DestDir:        sql.NullString{String: formData.DestDir, Valid: true},
 			RetentionDays:  sql.NullInt16{Int16: formData.RetentionDays, Valid: true},
+			DumpRateLimit:  sql.NullInt32{Int32: formData.DumpRateLimit, Valid: true},
+			KeepDaily:      sql.NullInt16{Int16: formData.KeepDaily, Valid: true},
+			KeepWeekly:     sql.NullInt16{Int16: formData.KeepWeekly, Valid: true},
+			KeepMonthly:    sql.NullInt16{Int16: formData.KeepMonthly, Valid: true},
+			KeepYearly:     sql.NullInt16{Int16: formData.KeepYearly, Valid: true},
+			KeepLast:       sql.NullInt16{Int16: formData.KeepLast, Valid: true},
+			CatchUpPolicy:  sql.NullString{String: formData.CatchUpPolicy, Valid: true},
 			OptDataOnly:    sql.NullBool{Bool: formData.OptDataOnly == "true", Valid: true},
 			OptSchemaOnly:  sql.NullBool{Bool: formData.OptSchemaOnly == "true", Valid: true},
 			OptClean:       sql.NullBool{Bool: formData.OptClean == "true", Valid: true},
 			OptIfExists:    sql.NullBool{Bool: formData.OptIfExists == "true", Valid: true},
 			OptCreate:      sql.NullBool{Bool: formData.OptCreate == "true", Valid: true},
 			OptNoComments:  sql.NullBool{Bool: formData.OptNoComments == "true", Valid: true},
+
+			SetRunAfter:      true,
+			RunAfterBackupID: runAfterBackupID,
+			RunAfterCondition: sql.NullString{
+				String: formData.RunAfterCondition,
+				Valid:  formData.RunAfterCondition != "",
+			},
 		},
 	)
 	if err != nil {
@@ -69,7 +103,9 @@ func (h *handlers) editBackupHandler(c echo.Context) error {
 	return respondhtmx.AlertWithRefresh(c, "Backup task updated")
 }
 
-func editBackupButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
+func editBackupButton(
+	backup dbgen.BackupsServicePaginateBackupsRow, allBackups []dbgen.Backup,
+) nodx.Node {
 	yesNoOptions := func(value bool) nodx.Node {
 		return nodx.Group(
 			nodx.Option(
@@ -85,15 +121,22 @@ func editBackupButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
 		)
 	}
 
+	formID := "edit-backup-" + backup.ID.String() + "-form"
+
 	mo := component.Modal(component.ModalParams{
 		Size:  component.SizeLg,
 		Title: "Edit backup task",
 		Content: []nodx.Node{
 			nodx.FormEl(
+				nodx.Id(formID),
 				htmx.HxPost("/dashboard/backups/"+backup.ID.String()+"/edit"),
 				htmx.HxDisabledELT("find button"),
 				nodx.Class("space-y-2 text-base"),
 
+				alpine.XData(fmt.Sprintf(
+					`{ trigger: %q }`, triggerOf(backup.RunAfterBackupID),
+				)),
+
 				component.InputControl(component.InputControlParams{
 					Name:        "name",
 					Label:       "Name",
@@ -105,13 +148,15 @@ func editBackupButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
 					},
 				}),
 
+				triggerSelect(),
+
 				component.InputControl(component.InputControlParams{
 					Name:        "cron_expression",
 					Label:       "Cron expression",
 					Placeholder: "* * * * *",
 					Required:    true,
 					Type:        component.InputTypeText,
-					HelpText:    "The cron expression to schedule the backup",
+					HelpText:    cronExpressionHelpText,
 					Pattern:     `^\S+\s+\S+\s+\S+\s+\S+\s+\S+$`,
 					Children: []nodx.Node{
 						nodx.Value(backup.CronExpression),
@@ -119,6 +164,11 @@ func editBackupButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
 					HelpButtonChildren: cronExpressionHelp(),
 				}),
 
+				runAfterControls(
+					allBackups, backup.ID, backup.RunAfterBackupID,
+					backup.RunAfterCondition,
+				),
+
 				component.SelectControl(component.SelectControlParams{
 					Name:        "time_zone",
 					Label:       "Time zone",
@@ -142,6 +192,8 @@ func editBackupButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
 					HelpButtonChildren: timezoneFilenamesHelp(),
 				}),
 
+				catchUpPolicySelect(backup.CatchUpPolicy),
+
 				component.InputControl(component.InputControlParams{
 					Name:               "dest_dir",
 					Label:              "Destination directory",
@@ -171,6 +223,30 @@ func editBackupButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
 					},
 				}),
 
+				keepLastInput(backup.KeepLast),
+
+				gfsRetentionOptions(gfsRetentionValues{
+					KeepDaily:   backup.KeepDaily,
+					KeepWeekly:  backup.KeepWeekly,
+					KeepMonthly: backup.KeepMonthly,
+					KeepYearly:  backup.KeepYearly,
+				}),
+
+				retentionPreviewContainer(backup.ID, formID),
+
+				component.InputControl(component.InputControlParams{
+					Name:        "dump_rate_limit",
+					Label:       "pg_dump read limit (KiB/s)",
+					Placeholder: "0",
+					Type:        component.InputTypeNumber,
+					Pattern:     "[0-9]+",
+					HelpText:    "Maximum speed, in KiB per second, at which the pg_dump output is read, use 0 for no limit",
+					Children: []nodx.Node{
+						nodx.Min("0"),
+						nodx.Value(fmt.Sprintf("%d", backup.DumpRateLimit)),
+					},
+				}),
+
 				component.SelectControl(component.SelectControlParams{
 					Name:     "is_active",
 					Label:    "Activate backup",
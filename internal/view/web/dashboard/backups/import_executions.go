@@ -0,0 +1,128 @@
+package backups
+
+import (
+	"fmt"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) importExecutionsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		Prefix string `form:"prefix"`
+		Pin    bool   `form:"pin"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	imported, skipped, err := h.servs.ExecutionsService.ImportExecutions(
+		ctx, backupID, formData.Prefix, formData.Pin,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return respondhtmx.AlertWithRefresh(c, fmt.Sprintf(
+		"%d executions imported, %d files were already registered",
+		imported, skipped,
+	))
+}
+
+func importExecutionsButton(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
+	hasRetention := backup.RetentionDays > 0 || backup.KeepLast > 0 ||
+		backup.KeepDaily > 0 || backup.KeepWeekly > 0 ||
+		backup.KeepMonthly > 0 || backup.KeepYearly > 0
+
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Import existing files",
+		Content: []nodx.Node{
+			nodx.FormEl(
+				htmx.HxPost("/dashboard/backups/"+backup.ID.String()+"/import"),
+				htmx.HxDisabledELT("find button"),
+				htmx.HxConfirm("Are you sure you want to import the files as executions of this backup?"),
+				nodx.Class("space-y-2"),
+
+				component.PText(`
+					Discover the dump files (.zip, .sql, .gz, .dump and .backup) stored
+					in the destination of this backup and register them as successful
+					executions, so they can be restored. Files already registered are
+					skipped.
+				`),
+
+				nodx.If(
+					hasRetention,
+					nodx.Div(
+						nodx.Class("alert alert-warning"),
+						lucide.TriangleAlert(),
+						component.SpanText(
+							"This backup has retention rules and the imported executions "+
+								"keep the date of their files, unpinned old files will be "+
+								"deleted by the next retention run.",
+						),
+					),
+				),
+
+				component.InputControl(component.InputControlParams{
+					Name:        "prefix",
+					Label:       "Directory",
+					Placeholder: "/path/to/backups",
+					Type:        component.InputTypeText,
+					HelpText:    "Directory (or S3 prefix) to scan, it defaults to the backup destination directory",
+					Children: []nodx.Node{
+						nodx.Value(backup.DestDir),
+					},
+				}),
+
+				component.SelectControl(component.SelectControlParams{
+					Name:     "pin",
+					Label:    "Pin imported executions",
+					Required: true,
+					HelpText: "Pinned executions are never deleted by the retention rules, unpin them to let the retention handle them",
+					Children: []nodx.Node{
+						nodx.Option(nodx.Value("true"), nodx.Text("Yes"), nodx.Selected("")),
+						nodx.Option(nodx.Value("false"), nodx.Text("No")),
+					},
+				}),
+
+				nodx.Div(
+					nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+					component.HxLoadingMd(),
+					nodx.Button(
+						nodx.Class("btn btn-primary"),
+						nodx.Type("submit"),
+						component.SpanText("Import files"),
+						lucide.Import(),
+					),
+				),
+			),
+		},
+	})
+
+	return nodx.Div(
+		mo.HTML,
+		component.OptionsDropdownButton(
+			mo.OpenerAttr,
+			lucide.Import(),
+			component.SpanText("Import existing files"),
+		),
+	)
+}
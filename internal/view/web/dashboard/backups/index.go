@@ -22,7 +22,11 @@ func indexPage(reqCtx reqctx.Ctx) nodx.Node {
 		nodx.Div(
 			nodx.Class("flex justify-between items-start"),
 			component.H1Text("Backup tasks"),
-			createBackupButton(),
+			nodx.Div(
+				nodx.Class("flex items-center space-x-2"),
+				upcomingRunsButton(),
+				createBackupButton(),
+			),
 		),
 		component.CardBox(component.CardBoxParams{
 			Class: "mt-4",
@@ -38,6 +42,7 @@ func indexPage(reqCtx reqctx.Ctx) nodx.Node {
 								nodx.Th(component.SpanText("Database")),
 								nodx.Th(component.SpanText("Destination")),
 								nodx.Th(component.SpanText("Schedule")),
+								nodx.Th(component.SpanText("Next runs")),
 								nodx.Th(component.SpanText("Retention")),
 								nodx.Th(component.SpanText("--data-only")),
 								nodx.Th(component.SpanText("--schema-only")),
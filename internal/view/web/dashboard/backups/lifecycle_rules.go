@@ -0,0 +1,240 @@
+package backups
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) lifecycleRulesHandler(c echo.Context) error {
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	return h.renderLifecycleRules(c, backupID)
+}
+
+func (h *handlers) createLifecycleRuleHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		DestinationID uuid.UUID `form:"destination_id" validate:"required,uuid"`
+		AfterDays     int16     `form:"after_days" validate:"required,min=1"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	_, err = h.servs.BackupsService.CreateLifecycleRule(
+		ctx, dbgen.BackupsServiceCreateLifecycleRuleParams{
+			BackupID:      backupID,
+			DestinationID: formData.DestinationID,
+			AfterDays:     formData.AfterDays,
+		},
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return h.renderLifecycleRules(c, backupID)
+}
+
+func (h *handlers) deleteLifecycleRuleHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	err = h.servs.BackupsService.DeleteLifecycleRule(ctx, backupID, ruleID)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return h.renderLifecycleRules(c, backupID)
+}
+
+func (h *handlers) renderLifecycleRules(c echo.Context, backupID uuid.UUID) error {
+	ctx := c.Request().Context()
+
+	rules, err := h.servs.BackupsService.GetLifecycleRules(ctx, backupID)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	destinations, err := h.servs.DestinationsService.GetAllDestinations(ctx)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, lifecycleRules(
+		backupID, rules, destinations,
+	))
+}
+
+func lifecycleRules(
+	backupID uuid.UUID,
+	rules []dbgen.BackupsServiceGetLifecycleRulesRow,
+	destinations []dbgen.DestinationsServiceGetAllDestinationsRow,
+) nodx.Node {
+	baseURL := "/dashboard/backups/" + backupID.String() + "/lifecycle"
+	containerID := "lifecycle-rules-" + backupID.String()
+
+	return nodx.Div(
+		nodx.Id(containerID),
+		nodx.Class("space-y-4 text-base"),
+
+		component.PText(`
+			Lifecycle rules move the successful executions of this backup to
+			another destination once they are older than the given number of
+			days. When several rules match, the one with the most days wins. Files
+			are verified after every move and rules are applied every 10 minutes.
+		`),
+
+		nodx.Div(
+			nodx.Class("overflow-x-auto"),
+			nodx.Table(
+				nodx.Class("table"),
+				nodx.Thead(
+					nodx.Tr(
+						nodx.Th(component.SpanText("After")),
+						nodx.Th(component.SpanText("Move to")),
+						nodx.Th(nodx.Class("w-1")),
+					),
+				),
+				nodx.Tbody(
+					nodx.If(
+						len(rules) == 0,
+						nodx.Tr(nodx.Td(
+							nodx.Colspan("3"),
+							component.SpanText("No lifecycle rules yet"),
+						)),
+					),
+					nodx.Map(
+						rules,
+						func(rule dbgen.BackupsServiceGetLifecycleRulesRow) nodx.Node {
+							return nodx.Tr(
+								nodx.Td(component.SpanText(
+									fmt.Sprintf("%d days", rule.AfterDays),
+								)),
+								nodx.Td(component.PrettyDestinationName(
+									rule.DestinationIsLocal,
+									sql.NullString{Valid: true, String: rule.DestinationName},
+								)),
+								nodx.Td(nodx.Button(
+									nodx.Class("btn btn-sm btn-ghost btn-square"),
+									nodx.Type("button"),
+									htmx.HxDelete(baseURL+"/"+rule.ID.String()),
+									htmx.HxConfirm("Are you sure you want to delete this rule?"),
+									htmx.HxTarget("#"+containerID),
+									htmx.HxSwap("outerHTML"),
+									lucide.Trash(),
+								)),
+							)
+						},
+					),
+				),
+			),
+		),
+
+		nodx.FormEl(
+			htmx.HxPost(baseURL),
+			htmx.HxTarget("#"+containerID),
+			htmx.HxSwap("outerHTML"),
+			htmx.HxDisabledELT("find button"),
+			nodx.Class("space-y-2"),
+
+			component.InputControl(component.InputControlParams{
+				Name:        "after_days",
+				Label:       "After days",
+				Placeholder: "30",
+				Type:        component.InputTypeNumber,
+				Required:    true,
+				HelpText:    "Age of the execution, in days, after which it is moved",
+				Children: []nodx.Node{
+					nodx.Min("1"),
+					nodx.Max("32767"),
+				},
+			}),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:        "destination_id",
+				Label:       "Move to",
+				Required:    true,
+				Placeholder: "Select a destination",
+				Children: []nodx.Node{
+					nodx.Map(
+						destinations,
+						func(dest dbgen.DestinationsServiceGetAllDestinationsRow) nodx.Node {
+							return nodx.Option(
+								nodx.Value(dest.ID.String()),
+								nodx.Text(prettyDestinationOption(dest.Name, dest.IsLocal)),
+							)
+						},
+					),
+				},
+			}),
+
+			nodx.Div(
+				nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+				component.HxLoadingMd(),
+				nodx.Button(
+					nodx.Class("btn btn-primary"),
+					nodx.Type("submit"),
+					component.SpanText("Add rule"),
+					lucide.Plus(),
+				),
+			),
+		),
+	)
+}
+
+func lifecycleRulesButton(backupID uuid.UUID) nodx.Node {
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Lifecycle rules",
+		Content: []nodx.Node{
+			nodx.Div(
+				htmx.HxGet("/dashboard/backups/"+backupID.String()+"/lifecycle"),
+				htmx.HxSwap("outerHTML"),
+				htmx.HxTrigger("intersect once"),
+				nodx.Class("p-10 flex justify-center"),
+				component.HxLoadingMd(),
+			),
+		},
+	})
+
+	return nodx.Div(
+		mo.HTML,
+		component.OptionsDropdownButton(
+			mo.OpenerAttr,
+			lucide.Layers(),
+			component.SpanText("Lifecycle rules"),
+		),
+	)
+}
@@ -3,9 +3,11 @@ package backups
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/service/backups"
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
 	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
 	"github.com/eduardolat/pgbackweb/internal/util/paginateutil"
 	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
@@ -41,14 +43,29 @@ func (h *handlers) listBackupsHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
+	activeWindows, err := h.servs.BlackoutsService.GetActiveWindows(
+		ctx, time.Now(),
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	allBackups, err := h.servs.BackupsService.GetAllBackups(ctx)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
 	return echoutil.RenderNodx(
-		c, http.StatusOK, listBackups(pagination, backups),
+		c, http.StatusOK,
+		listBackups(pagination, backups, activeWindows, allBackups),
 	)
 }
 
 func listBackups(
 	pagination paginateutil.PaginateResponse,
 	backups []dbgen.BackupsServicePaginateBackupsRow,
+	activeWindows []blackouts.ActiveWindow,
+	allBackups []dbgen.Backup,
 ) nodx.Node {
 	if len(backups) < 1 {
 		return component.EmptyResultsTr(component.EmptyResultsParams{
@@ -77,9 +94,14 @@ func listBackups(
 					lucide.List(),
 					component.SpanText("Show executions"),
 				),
-				manualRunbutton(backup.ID),
-				editBackupButton(backup),
+				manualRunbutton(
+					backup.ID, blackoutWindowFor(activeWindows, backup.DatabaseID),
+				),
+				editBackupButton(backup, allBackups),
 				duplicateBackupButton(backup.ID),
+				importExecutionsButton(backup),
+				transferExecutionsButton(backup.ID),
+				lifecycleRulesButton(backup.ID),
 				deleteBackupButton(backup.ID),
 			)),
 			nodx.Td(
@@ -91,24 +113,25 @@ func listBackups(
 			),
 			nodx.Td(component.SpanText(backup.DatabaseName)),
 			nodx.Td(component.PrettyDestinationName(
-				backup.IsLocal, backup.DestinationName,
+				backup.IsLocal || backup.DestinationIsLocal.Bool, backup.DestinationName,
 			)),
+			nodx.Td(backupTrigger(backup)),
+			nodx.Td(nextRuns(backup)),
 			nodx.Td(
-				nodx.Class("font-mono"),
 				nodx.Div(
-					nodx.Class("flex flex-col items-start text-xs"),
-					component.SpanText(backup.CronExpression),
-					component.SpanText(backup.TimeZone),
-				),
-			),
-			nodx.Td(
-				nodx.If(
-					backup.RetentionDays == 0,
-					lucide.Infinity(),
-				),
-				nodx.If(
-					backup.RetentionDays > 0,
-					component.SpanText(fmt.Sprintf("%d days", backup.RetentionDays)),
+					nodx.Class("flex flex-col items-start"),
+					nodx.If(
+						backup.RetentionDays == 0,
+						lucide.Infinity(),
+					),
+					nodx.If(
+						backup.RetentionDays > 0,
+						component.SpanText(fmt.Sprintf("%d days", backup.RetentionDays)),
+					),
+					nodx.If(
+						backup.KeepLast > 0,
+						component.SpanText(fmt.Sprintf("Keep last %d", backup.KeepLast)),
+					),
 				),
 			),
 			nodx.Td(yesNoSpan(backup.OptDataOnly)),
@@ -135,3 +158,50 @@ func listBackups(
 
 	return component.RenderableGroup(trs)
 }
+
+// backupTrigger shows the cron schedule of a backup, or the backup it runs
+// after if it is chained.
+func backupTrigger(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
+	if backup.RunAfterBackupID.Valid {
+		return nodx.Div(
+			nodx.Class("flex flex-col items-start text-xs"),
+			component.SpanText("After "+backup.RunAfterBackupName.String),
+			component.SpanText(runAfterConditionLabel(backup.RunAfterCondition)),
+		)
+	}
+
+	return nodx.Div(
+		nodx.Class("flex flex-col items-start text-xs font-mono"),
+		component.SpanText(backup.CronExpression),
+		component.SpanText(backup.TimeZone),
+	)
+}
+
+// nextRuns shows the next run times of a backup, or why it won't run.
+func nextRuns(backup dbgen.BackupsServicePaginateBackupsRow) nodx.Node {
+	if !backup.IsActive {
+		return component.SpanText("Paused")
+	}
+
+	if backup.RunAfterBackupID.Valid {
+		return component.SpanText("After " + backup.RunAfterBackupName.String)
+	}
+
+	runs, err := backups.NextRuns(backup.CronExpression, backup.TimeZone, 3)
+	if err != nil {
+		return nodx.SpanEl(
+			nodx.Class("badge badge-error"),
+			nodx.TitleAttr(err.Error()),
+			nodx.Text("Invalid schedule"),
+		)
+	}
+
+	return nodx.Div(
+		nodx.Class("flex flex-col items-start text-xs"),
+		nodx.Map(runs, func(run time.Time) nodx.Node {
+			return component.SpanText(
+				run.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
+			)
+		}),
+	)
+}
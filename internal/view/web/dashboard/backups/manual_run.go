@@ -1,8 +1,11 @@
 package backups
 
 import (
-	"context"
+	"fmt"
+	"time"
 
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
 	"github.com/eduardolat/pgbackweb/internal/view/web/component"
 	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
 	"github.com/google/uuid"
@@ -13,21 +16,73 @@ import (
 )
 
 func (h *handlers) manualRunHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
 	backupID, err := uuid.Parse(c.Param("backupID"))
 	if err != nil {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
-	go func() {
-		_ = h.servs.ExecutionsService.RunExecution(context.Background(), backupID)
-	}()
+	// Manual runs inside a blackout window need an explicit confirmation
+	if c.QueryParam("force") != "true" {
+		window, found, err := h.servs.BlackoutsService.GetBackupActiveWindow(
+			ctx, backupID, time.Now(),
+		)
+		if err != nil {
+			return respondhtmx.ToastError(c, err.Error())
+		}
+		if found {
+			return respondhtmx.ToastError(c, blackoutWindowWarning(window)+
+				", reload the page to run it anyway")
+		}
+	}
+
+	err = h.servs.ExecutionsService.RunExecution(ctx, backupID)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return respondhtmx.ToastSuccess(c, "Backup queued, check the backup executions for more details")
+}
 
-	return respondhtmx.ToastSuccess(c, "Backup started, check the backup executions for more details")
+// blackoutWindowFor returns the active blackout window that applies to the
+// backups of a database, if any.
+func blackoutWindowFor(
+	activeWindows []blackouts.ActiveWindow, databaseID uuid.UUID,
+) *blackouts.ActiveWindow {
+	window, found := blackouts.SelectWindow(activeWindows, databaseID)
+	if !found {
+		return nil
+	}
+	return &window
 }
 
-func manualRunbutton(backupID uuid.UUID) nodx.Node {
+func blackoutWindowWarning(window blackouts.ActiveWindow) string {
+	return fmt.Sprintf(
+		"The blackout window %q is active until %s", window.Name,
+		window.EndsAt.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
+	)
+}
+
+// manualRunbutton returns the button to run a backup right away. If the
+// backup is inside a blackout window, the run has to be confirmed.
+func manualRunbutton(
+	backupID uuid.UUID, window *blackouts.ActiveWindow,
+) nodx.Node {
+	if window == nil {
+		return component.OptionsDropdownButton(
+			htmx.HxPost("/dashboard/backups/"+backupID.String()+"/run"),
+			htmx.HxDisabledELT("this"),
+			lucide.Zap(),
+			component.SpanText("Run backup now"),
+		)
+	}
+
 	return component.OptionsDropdownButton(
-		htmx.HxPost("/dashboard/backups/"+backupID.String()+"/run"),
+		htmx.HxPost("/dashboard/backups/"+backupID.String()+"/run?force=true"),
+		htmx.HxConfirm(
+			blackoutWindowWarning(*window)+". Are you sure you want to run this backup now?",
+		),
 		htmx.HxDisabledELT("this"),
 		lucide.Zap(),
 		component.SpanText("Run backup now"),
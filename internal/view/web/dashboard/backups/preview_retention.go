@@ -0,0 +1,160 @@
+package backups
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+// retentionPreviewDays is the number of days evaluated by the retention
+// preview of the edit backup form.
+const retentionPreviewDays = 30
+
+func (h *handlers) previewRetentionHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	var formData struct {
+		TimeZone      string `form:"time_zone" validate:"required"`
+		RetentionDays int16  `form:"retention_days" validate:"min=0"`
+		KeepDaily     int16  `form:"keep_daily" validate:"min=0"`
+		KeepWeekly    int16  `form:"keep_weekly" validate:"min=0"`
+		KeepMonthly   int16  `form:"keep_monthly" validate:"min=0"`
+		KeepYearly    int16  `form:"keep_yearly" validate:"min=0"`
+		KeepLast      int16  `form:"keep_last" validate:"min=0"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	expirations, err := h.servs.ExecutionsService.PreviewRetention(
+		ctx, backupID, executions.RetentionSettings{
+			TimeZone:      formData.TimeZone,
+			RetentionDays: formData.RetentionDays,
+			KeepDaily:     formData.KeepDaily,
+			KeepWeekly:    formData.KeepWeekly,
+			KeepMonthly:   formData.KeepMonthly,
+			KeepYearly:    formData.KeepYearly,
+			KeepLast:      formData.KeepLast,
+		},
+		retentionPreviewDays,
+	)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, retentionPreview(expirations))
+}
+
+func retentionPreview(expirations []executions.RetentionExpiration) nodx.Node {
+	immediate := 0
+	for _, expiration := range expirations {
+		if expiration.Immediate {
+			immediate++
+		}
+	}
+
+	if len(expirations) == 0 {
+		return nodx.Div(
+			nodx.Class("alert alert-success"),
+			lucide.CircleCheck(),
+			component.SpanText(fmt.Sprintf(
+				"No executions would be deleted in the next %d days.",
+				retentionPreviewDays,
+			)),
+		)
+	}
+
+	return nodx.Div(
+		nodx.Class("space-y-2"),
+		nodx.If(
+			immediate > 0,
+			nodx.Div(
+				nodx.Class("alert alert-error"),
+				lucide.TriangleAlert(),
+				component.SpanText(fmt.Sprintf(
+					"%d executions would be deleted right away after saving.",
+					immediate,
+				)),
+			),
+		),
+		nodx.Div(
+			nodx.Class("alert alert-warning"),
+			lucide.Info(),
+			component.SpanText(fmt.Sprintf(
+				"%d executions would be deleted in the next %d days, assuming no new executions are created.",
+				len(expirations), retentionPreviewDays,
+			)),
+		),
+		nodx.Div(
+			nodx.Class("max-h-64 overflow-auto"),
+			nodx.Table(
+				nodx.Class("table table-sm text-nowrap"),
+				nodx.Thead(
+					nodx.Tr(
+						nodx.Th(component.SpanText("Finished at")),
+						nodx.Th(component.SpanText("File size")),
+						nodx.Th(component.SpanText("Deleted")),
+					),
+				),
+				nodx.Tbody(
+					nodx.Map(
+						expirations,
+						func(expiration executions.RetentionExpiration) nodx.Node {
+							deleted := expiration.ExpiresAt.Local().Format(timeutil.LayoutDashYYYYMMDD)
+							if expiration.Immediate {
+								deleted = "Right away"
+							}
+
+							return nodx.Tr(
+								nodx.Td(component.SpanText(
+									expiration.FinishedAt.Local().Format(
+										timeutil.LayoutYYYYMMDDHHMMSSPretty,
+									),
+								)),
+								nodx.Td(component.PrettyFileSize(expiration.FileSize)),
+								nodx.Td(component.SpanText(deleted)),
+							)
+						},
+					),
+				),
+			),
+		),
+	)
+}
+
+// retentionPreviewContainer renders the retention preview of a backup and
+// refreshes it every time the form changes.
+func retentionPreviewContainer(backupID uuid.UUID, formID string) nodx.Node {
+	return nodx.Div(
+		nodx.Class("pt-2"),
+		component.H2Text("Retention preview"),
+		nodx.Div(
+			nodx.Class("mt-2"),
+			htmx.HxPost("/dashboard/backups/"+backupID.String()+"/retention-preview"),
+			htmx.HxInclude("#"+formID),
+			htmx.HxTrigger("intersect once, change from:#"+formID+" delay:500ms"),
+			nodx.Div(
+				nodx.Class("flex justify-center"),
+				component.HxLoadingMd(),
+			),
+		),
+	)
+}
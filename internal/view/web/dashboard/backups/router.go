@@ -21,10 +21,19 @@ func MountRouter(
 
 	parent.GET("", h.indexPageHandler)
 	parent.GET("/list", h.listBackupsHandler)
+	parent.GET("/schedule", h.upcomingRunsPageHandler)
+	parent.GET("/schedule/list", h.listUpcomingRunsHandler)
 	parent.GET("/create-form", h.createBackupFormHandler)
 	parent.POST("", h.createBackupHandler)
 	parent.DELETE("/:backupID", h.deleteBackupHandler)
 	parent.POST("/:backupID/edit", h.editBackupHandler)
+	parent.POST("/:backupID/retention-preview", h.previewRetentionHandler)
 	parent.POST("/:backupID/run", h.manualRunHandler)
 	parent.POST("/:backupID/duplicate", h.duplicateBackupHandler)
+	parent.POST("/:backupID/import", h.importExecutionsHandler)
+	parent.GET("/:backupID/transfer-form", h.transferExecutionsFormHandler)
+	parent.POST("/:backupID/transfer", h.transferExecutionsHandler)
+	parent.GET("/:backupID/lifecycle", h.lifecycleRulesHandler)
+	parent.POST("/:backupID/lifecycle", h.createLifecycleRuleHandler)
+	parent.DELETE("/:backupID/lifecycle/:ruleID", h.deleteLifecycleRuleHandler)
 }
@@ -0,0 +1,179 @@
+package backups
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) transferExecutionsHandler(c echo.Context) error {
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		DestinationID uuid.UUID `form:"destination_id" validate:"required,uuid"`
+		SetBackupDest string    `form:"set_backup_destination" validate:"required,oneof=true false"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	// The backup is moved first, so the executions created during the
+	// transfer are already stored in the target destination
+	if formData.SetBackupDest == "true" {
+		_, err := h.servs.BackupsService.SetBackupDestination(
+			c.Request().Context(), backupID, formData.DestinationID,
+		)
+		if err != nil {
+			return respondhtmx.ToastError(c, err.Error())
+		}
+	}
+
+	go func() {
+		ctx := context.Background()
+		transferred, err := h.servs.ExecutionsService.TransferBackupExecutions(
+			ctx, backupID, formData.DestinationID,
+		)
+		if err != nil {
+			logger.Error("error transferring backup executions", logger.KV{
+				"backup_id":      backupID.String(),
+				"destination_id": formData.DestinationID.String(),
+				"transferred":    transferred,
+				"error":          err.Error(),
+			})
+			return
+		}
+		logger.Info("backup executions transferred", logger.KV{
+			"backup_id":      backupID.String(),
+			"destination_id": formData.DestinationID.String(),
+			"transferred":    transferred,
+		})
+	}()
+
+	return respondhtmx.ToastSuccess(
+		c, "Transfer started, check the executions page for more details",
+	)
+}
+
+func (h *handlers) transferExecutionsFormHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	backupID, err := uuid.Parse(c.Param("backupID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	destinations, err := h.servs.DestinationsService.GetAllDestinations(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, transferExecutionsForm(
+		backupID, destinations,
+	))
+}
+
+func transferExecutionsForm(
+	backupID uuid.UUID,
+	destinations []dbgen.DestinationsServiceGetAllDestinationsRow,
+) nodx.Node {
+	return nodx.FormEl(
+		htmx.HxPost("/dashboard/backups/"+backupID.String()+"/transfer"),
+		htmx.HxConfirm("Are you sure you want to transfer all the executions of this backup?"),
+		htmx.HxDisabledELT("find button"),
+		nodx.Class("space-y-2 text-base"),
+
+		component.PText(`
+			Move the files of all the successful executions of this backup to
+			another destination. Every file is verified (size and SHA-256 checksum)
+			before its execution is updated to point to the new destination and
+			the original file is deleted, so restores keep working.
+		`),
+		component.PText(`
+			Move the backup too to store its new executions in the target
+			destination. Once nothing is left in the source destination it can
+			be deleted without losing the transferred executions.
+		`),
+
+		component.SelectControl(component.SelectControlParams{
+			Name:        "destination_id",
+			Label:       "Target destination",
+			Required:    true,
+			Placeholder: "Select a destination",
+			Children: []nodx.Node{
+				nodx.Map(
+					destinations,
+					func(dest dbgen.DestinationsServiceGetAllDestinationsRow) nodx.Node {
+						return nodx.Option(
+							nodx.Value(dest.ID.String()),
+							nodx.Text(prettyDestinationOption(dest.Name, dest.IsLocal)),
+						)
+					},
+				),
+			},
+		}),
+
+		component.SelectControl(component.SelectControlParams{
+			Name:     "set_backup_destination",
+			Label:    "Move the backup too",
+			Required: true,
+			HelpText: "Store the new executions of the backup in the target destination",
+			Children: []nodx.Node{
+				nodx.Option(nodx.Value("true"), nodx.Text("Yes"), nodx.Selected("")),
+				nodx.Option(nodx.Value("false"), nodx.Text("No")),
+			},
+		}),
+
+		nodx.Div(
+			nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+			component.HxLoadingMd(),
+			nodx.Button(
+				nodx.Class("btn btn-primary"),
+				nodx.Type("submit"),
+				component.SpanText("Transfer executions"),
+				lucide.ArrowLeftRight(),
+			),
+		),
+	)
+}
+
+func transferExecutionsButton(backupID uuid.UUID) nodx.Node {
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Transfer executions",
+		Content: []nodx.Node{
+			nodx.Div(
+				htmx.HxGet("/dashboard/backups/"+backupID.String()+"/transfer-form"),
+				htmx.HxSwap("outerHTML"),
+				htmx.HxTrigger("intersect once"),
+				nodx.Class("p-10 flex justify-center"),
+				component.HxLoadingMd(),
+			),
+		},
+	})
+
+	return nodx.Div(
+		mo.HTML,
+		component.OptionsDropdownButton(
+			mo.OpenerAttr,
+			lucide.ArrowLeftRight(),
+			component.SpanText("Transfer executions"),
+		),
+	)
+}
@@ -0,0 +1,233 @@
+package backups
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/service/backups"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/reqctx"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/layout"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+type upcomingRunsQueryData struct {
+	Days int `query:"days" validate:"omitempty,min=1,max=31"`
+}
+
+func (q *upcomingRunsQueryData) days() int {
+	if q.Days == 0 {
+		return 7
+	}
+	return q.Days
+}
+
+func (h *handlers) upcomingRunsPageHandler(c echo.Context) error {
+	reqCtx := reqctx.GetCtx(c)
+
+	var queryData upcomingRunsQueryData
+	if err := c.Bind(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := validate.Struct(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	return echoutil.RenderNodx(
+		c, http.StatusOK, upcomingRunsPage(reqCtx, queryData.days()),
+	)
+}
+
+func (h *handlers) listUpcomingRunsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var queryData upcomingRunsQueryData
+	if err := c.Bind(&queryData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&queryData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	schedule, err := h.servs.BackupsService.GetUpcomingRuns(
+		ctx, queryData.days(),
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, listUpcomingRuns(schedule))
+}
+
+func upcomingRunsPage(reqCtx reqctx.Ctx, days int) nodx.Node {
+	content := []nodx.Node{
+		nodx.Div(
+			nodx.Class("flex justify-between items-start"),
+			component.H1Text("Upcoming runs"),
+			nodx.FormEl(
+				nodx.Method("GET"),
+				nodx.Class("flex items-end space-x-2"),
+				component.SelectControl(component.SelectControlParams{
+					Name:  "days",
+					Label: "Next",
+					Children: []nodx.Node{
+						nodx.Map(
+							[]int{1, 3, 7, 14, 31},
+							func(d int) nodx.Node {
+								return nodx.Option(
+									nodx.Value(fmt.Sprintf("%d", d)),
+									nodx.Text(fmt.Sprintf("%d days", d)),
+									nodx.If(d == days, nodx.Selected("")),
+								)
+							},
+						),
+					},
+				}),
+				nodx.Button(
+					nodx.Class("btn btn-primary"),
+					nodx.Type("submit"),
+					component.SpanText("Show"),
+				),
+			),
+		),
+		component.PText(`
+			Scheduled runs of the active backups, grouped by day. Runs scheduled at
+			the same minute as other backups are highlighted, along with the size of
+			the last successful backup, to help spotting heavy backups running
+			together.
+		`),
+		component.CardBox(component.CardBoxParams{
+			Class: "mt-4",
+			Children: []nodx.Node{
+				nodx.Div(
+					nodx.Class("overflow-x-auto"),
+					nodx.Table(
+						nodx.Class("table text-nowrap"),
+						nodx.Thead(
+							nodx.Tr(
+								nodx.Th(component.SpanText("Time")),
+								nodx.Th(component.SpanText("Backup")),
+								nodx.Th(component.SpanText("Database")),
+								nodx.Th(component.SpanText("Last size")),
+								nodx.Th(component.SpanText("Overlaps")),
+							),
+						),
+						nodx.Tbody(
+							component.SkeletonTr(8),
+							htmx.HxGet(fmt.Sprintf(
+								"/dashboard/backups/schedule/list?days=%d", days,
+							)),
+							htmx.HxTrigger("load"),
+						),
+					),
+				),
+			},
+		}),
+	}
+
+	return layout.Dashboard(reqCtx, layout.DashboardParams{
+		Title: "Upcoming runs",
+		Body:  content,
+	})
+}
+
+func listUpcomingRuns(schedule backups.UpcomingSchedule) nodx.Node {
+	trs := []nodx.Node{}
+
+	for _, invalid := range schedule.Invalid {
+		trs = append(trs, nodx.Tr(
+			nodx.Td(nodx.SpanEl(
+				nodx.Class("badge badge-error"),
+				nodx.Text("Invalid schedule"),
+			)),
+			nodx.Td(component.SpanText(invalid.BackupName)),
+			nodx.Td(
+				nodx.Colspan("3"),
+				nodx.Class("font-mono text-xs"),
+				component.SpanText(fmt.Sprintf(
+					"%s (%s): %s",
+					invalid.CronExpression, invalid.TimeZone, invalid.Error,
+				)),
+			),
+		))
+	}
+
+	if len(schedule.Runs) < 1 && len(trs) < 1 {
+		return component.EmptyResultsTr(component.EmptyResultsParams{
+			Title:    "No upcoming runs",
+			Subtitle: "No active backups are scheduled in this period",
+		})
+	}
+
+	day := ""
+	for _, run := range schedule.Runs {
+		scheduledAt := run.ScheduledAt.Local()
+
+		if runDay := scheduledAt.Format(timeutil.LayoutDashYYYYMMDD); runDay != day {
+			day = runDay
+			trs = append(trs, nodx.Tr(
+				nodx.Class("bg-base-200"),
+				nodx.Td(
+					nodx.Colspan("5"),
+					component.BText(scheduledAt.Format("Monday, ")+day),
+				),
+			))
+		}
+
+		overlaps := component.SpanText("-")
+		if run.Overlaps > 0 {
+			overlaps = nodx.SpanEl(
+				nodx.Class("badge badge-warning"),
+				nodx.Text(fmt.Sprintf("+%d at the same time", run.Overlaps)),
+			)
+		}
+
+		trs = append(trs, nodx.Tr(
+			nodx.Td(
+				nodx.Class("font-mono"),
+				component.SpanText(scheduledAt.Format("15:04")),
+			),
+			nodx.Td(nodx.A(
+				nodx.Class("link"),
+				nodx.Href("/dashboard/executions?backup="+run.BackupID.String()),
+				component.SpanText(run.BackupName),
+			)),
+			nodx.Td(component.SpanText(run.DatabaseName)),
+			nodx.Td(component.PrettyFileSize(sql.NullInt64{
+				Valid: run.LastFileSize > 0, Int64: run.LastFileSize,
+			})),
+			nodx.Td(overlaps),
+		))
+	}
+
+	if schedule.Truncated {
+		trs = append(trs, nodx.Tr(
+			nodx.Td(
+				nodx.Colspan("5"),
+				component.PText(`
+					Some backups run too often to show all their runs, only their
+					first runs in the period are shown.
+				`),
+			),
+		))
+	}
+
+	return component.RenderableGroup(trs)
+}
+
+func upcomingRunsButton() nodx.Node {
+	return nodx.A(
+		nodx.Href("/dashboard/backups/schedule"),
+		nodx.Class("btn btn-ghost"),
+		lucide.CalendarDays(),
+		component.SpanText("Upcoming runs"),
+	)
+}
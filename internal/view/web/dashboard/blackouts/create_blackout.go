@@ -0,0 +1,296 @@
+package blackouts
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
+	"github.com/eduardolat/pgbackweb/internal/staticdata"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	alpine "github.com/nodxdev/nodxgo-alpine"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+// datetimeLocalLayout is the layout of the values of the datetime-local
+// inputs.
+const datetimeLocalLayout = "2006-01-02T15:04"
+
+func (h *handlers) createBlackoutHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var formData struct {
+		Name            string `form:"name" validate:"required"`
+		DatabaseID      string `form:"database_id" validate:"omitempty,uuid"`
+		Action          string `form:"action" validate:"required,oneof=skip defer"`
+		IsRecurring     string `form:"is_recurring" validate:"required,oneof=true false"`
+		TimeZone        string `form:"time_zone" validate:"required"`
+		StartsAt        string `form:"starts_at"`
+		EndsAt          string `form:"ends_at"`
+		CronExpression  string `form:"cron_expression"`
+		DurationMinutes int32  `form:"duration_minutes" validate:"min=0"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	loc, err := time.LoadLocation(formData.TimeZone)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	// An empty database means the window applies to all the databases
+	databaseID := uuid.NullUUID{}
+	if formData.DatabaseID != "" {
+		databaseID = uuid.NullUUID{
+			Valid: true, UUID: uuid.MustParse(formData.DatabaseID),
+		}
+	}
+
+	params := dbgen.BlackoutsServiceCreateWindowParams{
+		DatabaseID: databaseID,
+		Name:       formData.Name,
+		Action:     formData.Action,
+	}
+
+	if formData.IsRecurring == "true" {
+		params.CronExpression = sql.NullString{
+			Valid: true, String: formData.CronExpression,
+		}
+		params.TimeZone = sql.NullString{Valid: true, String: formData.TimeZone}
+		params.DurationMinutes = sql.NullInt32{
+			Valid: true, Int32: formData.DurationMinutes,
+		}
+	}
+
+	if formData.IsRecurring == "false" {
+		startsAt, err := time.ParseInLocation(
+			datetimeLocalLayout, formData.StartsAt, loc,
+		)
+		if err != nil {
+			return respondhtmx.ToastError(c, "invalid start date")
+		}
+		endsAt, err := time.ParseInLocation(
+			datetimeLocalLayout, formData.EndsAt, loc,
+		)
+		if err != nil {
+			return respondhtmx.ToastError(c, "invalid end date")
+		}
+
+		params.StartsAt = sql.NullTime{Valid: true, Time: startsAt}
+		params.EndsAt = sql.NullTime{Valid: true, Time: endsAt}
+	}
+
+	if _, err := h.servs.BlackoutsService.CreateWindow(ctx, params); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return respondhtmx.Redirect(c, "/dashboard/blackouts")
+}
+
+func (h *handlers) createBlackoutFormHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	databases, err := h.servs.DatabasesService.GetAllDatabases(ctx)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, createBlackoutForm(databases))
+}
+
+func createBlackoutForm(
+	databases []dbgen.DatabasesServiceGetAllDatabasesRow,
+) nodx.Node {
+	serverTZ := time.Now().Location().String()
+
+	return nodx.FormEl(
+		htmx.HxPost("/dashboard/blackouts"),
+		htmx.HxDisabledELT("find button"),
+		nodx.Class("space-y-2 text-base"),
+
+		alpine.XData(`{
+			is_recurring: "false",
+		}`),
+
+		component.InputControl(component.InputControlParams{
+			Name:        "name",
+			Label:       "Name",
+			Placeholder: "Month-end processing",
+			Required:    true,
+			Type:        component.InputTypeText,
+			HelpText:    "A name to easily identify the blackout window",
+		}),
+
+		component.SelectControl(component.SelectControlParams{
+			Name:     "database_id",
+			Label:    "Database",
+			HelpText: "The window applies to the backups of this database",
+			Children: []nodx.Node{
+				nodx.Option(nodx.Value(""), nodx.Text("All databases")),
+				nodx.Map(
+					databases,
+					func(db dbgen.DatabasesServiceGetAllDatabasesRow) nodx.Node {
+						return nodx.Option(nodx.Value(db.ID.String()), nodx.Text(db.Name))
+					},
+				),
+			},
+		}),
+
+		component.SelectControl(component.SelectControlParams{
+			Name:     "action",
+			Label:    "Scheduled runs inside the window",
+			Required: true,
+			Children: []nodx.Node{
+				nodx.Option(
+					nodx.Value(blackouts.ActionDefer),
+					nodx.Text("Defer them until the window ends"),
+				),
+				nodx.Option(
+					nodx.Value(blackouts.ActionSkip),
+					nodx.Text("Skip them"),
+				),
+			},
+		}),
+
+		component.SelectControl(component.SelectControlParams{
+			Name:     "is_recurring",
+			Label:    "Repeat",
+			Required: true,
+			Children: []nodx.Node{
+				alpine.XModel("is_recurring"),
+				nodx.Option(nodx.Value("false"), nodx.Text("One-off"), nodx.Selected("")),
+				nodx.Option(nodx.Value("true"), nodx.Text("Recurring")),
+			},
+		}),
+
+		component.SelectControl(component.SelectControlParams{
+			Name:     "time_zone",
+			Label:    "Time zone",
+			Required: true,
+			HelpText: "The time zone of the dates or the cron expression",
+			Children: []nodx.Node{
+				nodx.Map(
+					staticdata.Timezones,
+					func(tz staticdata.Timezone) nodx.Node {
+						return nodx.Option(
+							nodx.Value(tz.TzCode),
+							nodx.Text(tz.Label),
+							nodx.If(tz.TzCode == serverTZ, nodx.Selected("")),
+						)
+					},
+				),
+			},
+		}),
+
+		alpine.Template(
+			alpine.XIf("is_recurring == 'false'"),
+			nodx.Div(
+				nodx.Class("grid grid-cols-2 gap-2"),
+				component.InputControl(component.InputControlParams{
+					Name:     "starts_at",
+					Label:    "Starts at",
+					Required: true,
+					Type:     component.InputTypeDatetime,
+				}),
+				component.InputControl(component.InputControlParams{
+					Name:     "ends_at",
+					Label:    "Ends at",
+					Required: true,
+					Type:     component.InputTypeDatetime,
+				}),
+			),
+		),
+
+		alpine.Template(
+			alpine.XIf("is_recurring == 'true'"),
+			nodx.Div(
+				nodx.Class("grid grid-cols-2 gap-2"),
+				component.InputControl(component.InputControlParams{
+					Name:               "cron_expression",
+					Label:              "Starts at (cron expression)",
+					Placeholder:        "0 18 L * *",
+					Required:           true,
+					Type:               component.InputTypeText,
+					HelpButtonChildren: recurringWindowHelp(),
+				}),
+				component.InputControl(component.InputControlParams{
+					Name:        "duration_minutes",
+					Label:       "Duration in minutes",
+					Placeholder: "360",
+					Required:    true,
+					Type:        component.InputTypeNumber,
+					Pattern:     "[0-9]+",
+					Children: []nodx.Node{
+						nodx.Min("1"),
+					},
+				}),
+			),
+		),
+
+		nodx.Div(
+			nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+			component.HxLoadingMd(),
+			nodx.Button(
+				nodx.Class("btn btn-primary"),
+				nodx.Type("submit"),
+				component.SpanText("Create blackout window"),
+				lucide.Save(),
+			),
+		),
+	)
+}
+
+func recurringWindowHelp() []nodx.Node {
+	return []nodx.Node{
+		component.PText(`
+			A recurring window starts at every run of the cron expression and lasts
+			the given duration.
+		`),
+		component.PText(fmt.Sprintf(
+			"For example, %q with a duration of %d minutes starts at 18:00 on the last day of every month and ends at 06:00 the next day.",
+			"0 18 L * *", 12*60,
+		)),
+	}
+}
+
+func createBlackoutButton() nodx.Node {
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Create blackout window",
+		Content: []nodx.Node{
+			nodx.Div(
+				htmx.HxGet("/dashboard/blackouts/create-form"),
+				htmx.HxSwap("outerHTML"),
+				htmx.HxTrigger("intersect once"),
+				nodx.Class("p-10 flex justify-center"),
+				component.HxLoadingMd(),
+			),
+		},
+	})
+
+	button := nodx.Button(
+		mo.OpenerAttr,
+		nodx.Class("btn btn-primary"),
+		component.SpanText("Create blackout window"),
+		lucide.Plus(),
+	)
+
+	return nodx.Div(
+		nodx.Class("inline-block"),
+		mo.HTML,
+		button,
+	)
+}
@@ -0,0 +1,35 @@
+package blackouts
+
+import (
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) deleteBlackoutHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	windowID, err := uuid.Parse(c.Param("windowID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	if err = h.servs.BlackoutsService.DeleteWindow(ctx, windowID); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return respondhtmx.Refresh(c)
+}
+
+func deleteBlackoutButton(windowID uuid.UUID) nodx.Node {
+	return component.OptionsDropdownButton(
+		htmx.HxDelete("/dashboard/blackouts/"+windowID.String()),
+		htmx.HxConfirm("Are you sure you want to delete this blackout window?"),
+		lucide.Trash(),
+		component.SpanText("Delete blackout window"),
+	)
+}
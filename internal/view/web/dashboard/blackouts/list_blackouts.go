@@ -0,0 +1,115 @@
+package blackouts
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/service/blackouts"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+)
+
+func (h *handlers) listBlackoutsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	windows, err := h.servs.BlackoutsService.GetAllWindows(ctx)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	active, err := h.servs.BlackoutsService.GetActiveWindows(ctx, time.Now())
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, listBlackouts(windows, active))
+}
+
+func listBlackouts(
+	windows []dbgen.BlackoutsServiceGetAllWindowsRow,
+	active []blackouts.ActiveWindow,
+) nodx.Node {
+	if len(windows) < 1 {
+		return component.EmptyResultsTr(component.EmptyResultsParams{
+			Title:    "No blackout windows found",
+			Subtitle: "Scheduled backups run without restrictions",
+		})
+	}
+
+	activeEnds := map[uuid.UUID]time.Time{}
+	for _, window := range active {
+		activeEnds[window.ID] = window.EndsAt
+	}
+
+	trs := []nodx.Node{}
+	for _, window := range windows {
+		database := component.SpanText("All databases")
+		if window.DatabaseName.Valid {
+			database = component.SpanText(window.DatabaseName.String)
+		}
+
+		action := "Skip runs"
+		if window.Action == blackouts.ActionDefer {
+			action = "Defer runs"
+		}
+
+		status := component.SpanText("Inactive")
+		if endsAt, ok := activeEnds[window.ID]; ok {
+			status = nodx.SpanEl(
+				nodx.Class("badge badge-warning"),
+				nodx.Text("Active until "+endsAt.Local().Format(
+					timeutil.LayoutYYYYMMDDHHMMSSPretty,
+				)),
+			)
+		}
+
+		trs = append(trs, nodx.Tr(
+			nodx.Td(component.OptionsDropdown(
+				deleteBlackoutButton(window.ID),
+			)),
+			nodx.Td(component.SpanText(window.Name)),
+			nodx.Td(database),
+			nodx.Td(component.SpanText(action)),
+			nodx.Td(windowWhen(window)),
+			nodx.Td(status),
+			nodx.Td(component.SpanText(
+				window.CreatedAt.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
+			)),
+		))
+	}
+
+	return component.RenderableGroup(trs)
+}
+
+func windowWhen(window dbgen.BlackoutsServiceGetAllWindowsRow) nodx.Node {
+	if !window.CronExpression.Valid {
+		return nodx.Div(
+			nodx.Class("flex flex-col items-start text-xs"),
+			component.SpanText("From "+window.StartsAt.Time.Local().Format(
+				timeutil.LayoutYYYYMMDDHHMMSSPretty,
+			)),
+			component.SpanText("To "+window.EndsAt.Time.Local().Format(
+				timeutil.LayoutYYYYMMDDHHMMSSPretty,
+			)),
+		)
+	}
+
+	return nodx.Div(
+		nodx.Class("flex flex-col items-start text-xs"),
+		nodx.SpanEl(
+			nodx.Class("font-mono"),
+			nodx.Text(window.CronExpression.String),
+		),
+		component.SpanText(fmt.Sprintf(
+			"%s, for %d minutes", window.TimeZone.String,
+			window.DurationMinutes.Int32,
+		)),
+	)
+}
@@ -0,0 +1,258 @@
+package destinations
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/integration/storage"
+	"github.com/eduardolat/pgbackweb/internal/service/destinations"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/reqctx"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/layout"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) auditDestinationPageHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+	reqCtx := reqctx.GetCtx(c)
+
+	destinationID, err := uuid.Parse(c.Param("destinationID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	dest, err := h.servs.DestinationsService.GetDestination(ctx, destinationID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return echoutil.RenderNodx(
+		c, http.StatusOK, auditDestinationPage(reqCtx, dest),
+	)
+}
+
+func (h *handlers) auditDestinationResultsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	destinationID, err := uuid.Parse(c.Param("destinationID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	result, err := h.servs.DestinationsService.AuditDestination(ctx, destinationID)
+	if err != nil {
+		return echoutil.RenderNodx(c, http.StatusOK, component.EmptyResults(
+			component.EmptyResultsParams{
+				Title:    "Error auditing destination",
+				Subtitle: err.Error(),
+			},
+		))
+	}
+
+	return echoutil.RenderNodx(
+		c, http.StatusOK, auditDestinationResults(destinationID, result),
+	)
+}
+
+func (h *handlers) cleanupDestinationOrphansHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	destinationID, err := uuid.Parse(c.Param("destinationID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		Paths []string `form:"paths" validate:"required,min=1"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	deleted, skipped, err := h.servs.DestinationsService.CleanupDestinationOrphans(
+		ctx, destinationID, formData.Paths,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	msg := fmt.Sprintf("%d orphaned files deleted", deleted)
+	if skipped > 0 {
+		msg += fmt.Sprintf(
+			", %d skipped because they are tracked or couldn't be deleted", skipped,
+		)
+	}
+	return respondhtmx.AlertWithRefresh(c, msg)
+}
+
+func auditDestinationPage(
+	reqCtx reqctx.Ctx, dest dbgen.DestinationsServiceGetDestinationRow,
+) nodx.Node {
+	content := []nodx.Node{
+		component.H1Text("Audit destination " + dest.Name),
+		component.PText(`
+			Files stored under the directory of every backup using this destination,
+			and under the directories of its executions, are compared against the
+			executions. Untracked files are dump files (.zip, .sql, .gz, .dump and
+			.backup) that no execution references, usually left by failed uploads.
+			Missing files are successful executions whose file no longer exists.
+		`),
+
+		nodx.Div(
+			nodx.Class("mt-4"),
+			htmx.HxGet("/dashboard/destinations/"+dest.ID.String()+"/audit/results"),
+			htmx.HxTrigger("load"),
+			nodx.Div(
+				nodx.Class("flex justify-center items-center space-x-2 py-8"),
+				component.SpinnerMd(),
+				component.SpanText("Listing files, this may take a while..."),
+			),
+		),
+	}
+
+	return layout.Dashboard(reqCtx, layout.DashboardParams{
+		Title: "Audit destination",
+		Body:  content,
+	})
+}
+
+func auditDestinationResults(
+	destinationID uuid.UUID, result destinations.AuditDestinationResult,
+) nodx.Node {
+	statCard := func(title, value string) nodx.Node {
+		return component.CardBox(component.CardBoxParams{
+			Children: []nodx.Node{
+				component.H3Text(title),
+				nodx.SpanEl(nodx.Class("text-2xl font-bold"), nodx.Text(value)),
+			},
+		})
+	}
+
+	untrackedTrs := []nodx.Node{}
+	for _, file := range result.UntrackedFiles {
+		untrackedTrs = append(untrackedTrs, nodx.Tr(
+			nodx.Td(nodx.Class("break-all"), component.SpanText(file.Path)),
+			nodx.Td(component.SpanText(strutil.FormatFileSize(file.Size))),
+		))
+	}
+	if len(untrackedTrs) == 0 {
+		untrackedTrs = append(untrackedTrs, component.EmptyResultsTr(
+			component.EmptyResultsParams{Title: "No untracked files found"},
+		))
+	}
+
+	missingTrs := []nodx.Node{}
+	for _, execution := range result.MissingFiles {
+		missingTrs = append(missingTrs, nodx.Tr(
+			nodx.Td(component.SpanText(execution.BackupName)),
+			nodx.Td(nodx.Class("break-all"), component.SpanText(execution.Path.String)),
+			nodx.Td(component.SpanText(
+				execution.StartedAt.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
+			)),
+		))
+	}
+	if len(missingTrs) == 0 {
+		missingTrs = append(missingTrs, component.EmptyResultsTr(
+			component.EmptyResultsParams{Title: "No missing files found"},
+		))
+	}
+
+	return nodx.Div(
+		nodx.Class("space-y-4"),
+
+		nodx.Div(
+			nodx.Class("grid grid-cols-2 lg:grid-cols-4 gap-4"),
+			statCard("Files", fmt.Sprintf("%d", result.TotalFiles)),
+			statCard("Bytes used", strutil.FormatFileSize(result.TotalBytes)),
+			statCard("Untracked", fmt.Sprintf(
+				"%d (%s)", len(result.UntrackedFiles),
+				strutil.FormatFileSize(result.UntrackedBytes),
+			)),
+			statCard("Missing", fmt.Sprintf("%d", len(result.MissingFiles))),
+		),
+
+		component.CardBox(component.CardBoxParams{
+			Children: []nodx.Node{
+				nodx.Div(
+					nodx.Class("flex justify-between items-center"),
+					component.H2Text("Untracked files"),
+					nodx.If(
+						len(result.UntrackedFiles) > 0 && result.CleanupError == "",
+						nodx.FormEl(
+							htmx.HxPost("/dashboard/destinations/"+destinationID.String()+"/audit/cleanup"),
+							htmx.HxConfirm(fmt.Sprintf(
+								"Are you sure you want to permanently delete %d untracked files (%s)? This action can't be undone.",
+								len(result.UntrackedFiles), strutil.FormatFileSize(result.UntrackedBytes),
+							)),
+							htmx.HxDisabledELT("find button"),
+							nodx.Map(result.UntrackedFiles, func(file storage.FileInfo) nodx.Node {
+								return nodx.Input(
+									nodx.Type("hidden"),
+									nodx.Name("paths"),
+									nodx.Value(file.Path),
+								)
+							}),
+							nodx.Button(
+								nodx.Type("submit"),
+								nodx.Class("btn btn-error"),
+								component.SpanText("Delete untracked files"),
+								lucide.Trash(),
+							),
+						),
+					),
+				),
+				nodx.If(
+					result.CleanupError != "",
+					nodx.Div(
+						nodx.Class("alert alert-warning mt-2"),
+						component.SpanText(
+							"The untracked files can't be deleted from here, "+result.CleanupError+".",
+						),
+					),
+				),
+				nodx.Div(
+					nodx.Class("overflow-x-auto mt-2"),
+					nodx.Table(
+						nodx.Class("table"),
+						nodx.Thead(nodx.Tr(
+							nodx.Th(component.SpanText("Path")),
+							nodx.Th(component.SpanText("Size")),
+						)),
+						nodx.Tbody(untrackedTrs...),
+					),
+				),
+			},
+		}),
+
+		component.CardBox(component.CardBoxParams{
+			Children: []nodx.Node{
+				component.H2Text("Missing files"),
+				nodx.Div(
+					nodx.Class("overflow-x-auto mt-2"),
+					nodx.Table(
+						nodx.Class("table"),
+						nodx.Thead(nodx.Tr(
+							nodx.Th(component.SpanText("Backup")),
+							nodx.Th(component.SpanText("Path")),
+							nodx.Th(component.SpanText("Started at")),
+						)),
+						nodx.Tbody(missingTrs...),
+					),
+				),
+			},
+		}),
+	)
+}
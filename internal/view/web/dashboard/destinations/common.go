@@ -0,0 +1,283 @@
+package destinations
+
+import (
+	"strconv"
+
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	nodx "github.com/nodxdev/nodxgo"
+)
+
+const localPathHelp = `
+	Absolute path of a directory in the server where PG Back Web is running.
+	Mount a docker volume to this path to persist the backups, for example
+	/mnt/backups-ssd or /mnt/backups-hdd.
+`
+
+const lowSpaceThresholdHelp = `
+	Run the "Storage low space" webhooks when the free space of the path drops
+	below this number of GiB. Use 0 to disable it.
+`
+
+// s3AdvancedValues are the current values of the S3 advanced options of a
+// destination, used to fill the create and edit forms.
+type s3AdvancedValues struct {
+	UsePathStyle   bool
+	StorageClass   string
+	SSE            string
+	SSEKMSKeyID    string
+	ObjectLockMode string
+	ObjectLockDays int32
+}
+
+func s3AdvancedOptionsHelp() []nodx.Node {
+	return []nodx.Node{
+		component.H3Text("Addressing style"),
+		component.PText(`
+			Path-style sends the bucket in the path of the request
+			(endpoint/bucket/key) and works with most S3 compatible providers.
+			Virtual-hosted style sends the bucket as a subdomain
+			(bucket.endpoint/key) and is the one recommended by AWS.
+		`),
+
+		nodx.Div(
+			nodx.Class("mt-2"),
+			component.H3Text("Storage class"),
+			component.PText(`
+				The storage class of the uploaded backups. Infrequent access classes
+				like STANDARD_IA or GLACIER_IR are cheaper to store but more expensive
+				to retrieve. Leave it empty to use the bucket default.
+			`),
+		),
+
+		nodx.Div(
+			nodx.Class("mt-2"),
+			component.H3Text("Server-side encryption"),
+			component.PText(`
+				SSE-S3 encrypts the backups with keys managed by S3. SSE-KMS uses a
+				KMS key, if the key id is empty the default KMS key of the account is
+				used.
+			`),
+		),
+
+		nodx.Div(
+			nodx.Class("mt-2"),
+			component.H3Text("Object lock"),
+			component.PText(`
+				Object lock makes the backups immutable for the given number of days,
+				so they can't be deleted or overwritten, not even by a compromised
+				account in COMPLIANCE mode. The bucket must be created with object
+				lock enabled. Deleting a locked backup from PG Back Web will fail
+				until the retention period ends.
+			`),
+		),
+	}
+}
+
+func s3AdvancedOptions(values s3AdvancedValues) nodx.Node {
+	option := func(value, text, current string) nodx.Node {
+		return nodx.Option(
+			nodx.Value(value),
+			nodx.Text(text),
+			nodx.If(value == current, nodx.Selected("")),
+		)
+	}
+
+	objectLockDays := ""
+	if values.ObjectLockDays > 0 {
+		objectLockDays = strconv.Itoa(int(values.ObjectLockDays))
+	}
+
+	return nodx.Div(
+		nodx.Class("pt-2"),
+		nodx.Div(
+			nodx.Class("flex justify-start items-center space-x-1"),
+			component.H2Text("Advanced options"),
+			component.HelpButtonModal(component.HelpButtonModalParams{
+				ModalTitle: "Advanced options",
+				Children:   s3AdvancedOptionsHelp(),
+			}),
+		),
+
+		nodx.Div(
+			nodx.Class("mt-2 grid grid-cols-2 gap-2"),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:     "use_path_style",
+				Label:    "Addressing style",
+				Required: true,
+				Children: []nodx.Node{
+					option("true", "Path-style", strconv.FormatBool(values.UsePathStyle)),
+					option("false", "Virtual-hosted", strconv.FormatBool(values.UsePathStyle)),
+				},
+			}),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:  "storage_class",
+				Label: "Storage class",
+				Children: []nodx.Node{
+					option("", "Bucket default", values.StorageClass),
+					option("STANDARD", "STANDARD", values.StorageClass),
+					option("STANDARD_IA", "STANDARD_IA", values.StorageClass),
+					option("ONEZONE_IA", "ONEZONE_IA", values.StorageClass),
+					option("INTELLIGENT_TIERING", "INTELLIGENT_TIERING", values.StorageClass),
+					option("GLACIER_IR", "GLACIER_IR", values.StorageClass),
+				},
+			}),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:  "sse",
+				Label: "Server-side encryption",
+				Children: []nodx.Node{
+					option("", "None", values.SSE),
+					option("AES256", "SSE-S3 (AES256)", values.SSE),
+					option("aws:kms", "SSE-KMS", values.SSE),
+				},
+			}),
+
+			component.InputControl(component.InputControlParams{
+				Name:        "sse_kms_key_id",
+				Label:       "KMS key id",
+				Placeholder: "Only for SSE-KMS",
+				Type:        component.InputTypeText,
+				Children: []nodx.Node{
+					nodx.Value(values.SSEKMSKeyID),
+				},
+			}),
+
+			component.SelectControl(component.SelectControlParams{
+				Name:  "object_lock_mode",
+				Label: "Object lock mode",
+				Children: []nodx.Node{
+					option("", "Disabled", values.ObjectLockMode),
+					option("GOVERNANCE", "GOVERNANCE", values.ObjectLockMode),
+					option("COMPLIANCE", "COMPLIANCE", values.ObjectLockMode),
+				},
+			}),
+
+			component.InputControl(component.InputControlParams{
+				Name:        "object_lock_days",
+				Label:       "Object lock days",
+				Placeholder: "30",
+				Type:        component.InputTypeNumber,
+				Children: []nodx.Node{
+					nodx.Min("1"),
+					nodx.Value(objectLockDays),
+				},
+			}),
+		),
+	)
+}
+
+// uploadLimitsValues are the current values of the upload limits of a
+// destination, used to fill the create and edit forms.
+type uploadLimitsValues struct {
+	UploadRateLimit   int32
+	UploadPartSize    int32
+	UploadConcurrency int32
+
+	MaxRunningExecutions int32
+}
+
+func uploadLimitsHelp() []nodx.Node {
+	return []nodx.Node{
+		component.H3Text("Upload rate limit"),
+		component.PText(`
+			Maximum average speed, in KiB per second, used to write the backups to
+			this destination. It applies to scheduled and manual backups, uploads
+			and transfers. Leave it at 0 to upload as fast as possible.
+		`),
+
+		nodx.Div(
+			nodx.Class("mt-2"),
+			component.H3Text("Part size and concurrency"),
+			component.PText(`
+				Only for S3 destinations. Backups are uploaded in parts of the given
+				size (between 5 and 1024 MiB), several parts at the same time.
+				Lower values use less memory and bandwidth, higher values are faster
+				for big backups. Leave them at 0 to use the defaults (5 MiB parts
+				and 5 parts at the same time).
+			`),
+			component.PText(`
+				Every part being uploaded is held in memory, so an upload uses up to
+				the part size × (concurrency + 1), which can't exceed 2048 MiB. Parts
+				smaller than 64 MiB grow up to 64 MiB for very big backups, within
+				that limit. With the defaults an upload uses at most 384 MiB.
+			`),
+		),
+
+		nodx.Div(
+			nodx.Class("mt-2"),
+			component.H3Text("Running backups"),
+			component.PText(`
+				Maximum number of backups stored in this destination at the same time,
+				the rest of the backups wait in the executions queue. Leave it at 0
+				for no limit.
+			`),
+		),
+	}
+}
+
+func uploadLimitsOptions(values uploadLimitsValues) nodx.Node {
+	return nodx.Div(
+		nodx.Class("pt-2"),
+		nodx.Div(
+			nodx.Class("flex justify-start items-center space-x-1"),
+			component.H2Text("Upload limits"),
+			component.HelpButtonModal(component.HelpButtonModalParams{
+				ModalTitle: "Upload limits",
+				Children:   uploadLimitsHelp(),
+			}),
+		),
+
+		nodx.Div(
+			nodx.Class("mt-2 grid grid-cols-3 gap-2"),
+
+			component.InputControl(component.InputControlParams{
+				Name:        "upload_rate_limit",
+				Label:       "Rate limit (KiB/s)",
+				Placeholder: "0",
+				Type:        component.InputTypeNumber,
+				Children: []nodx.Node{
+					nodx.Min("0"),
+					nodx.Value(strconv.Itoa(int(values.UploadRateLimit))),
+				},
+			}),
+
+			component.InputControl(component.InputControlParams{
+				Name:        "upload_part_size",
+				Label:       "Part size (MiB)",
+				Placeholder: "0",
+				Type:        component.InputTypeNumber,
+				Children: []nodx.Node{
+					nodx.Min("0"),
+					nodx.Max("1024"),
+					nodx.Value(strconv.Itoa(int(values.UploadPartSize))),
+				},
+			}),
+
+			component.InputControl(component.InputControlParams{
+				Name:        "upload_concurrency",
+				Label:       "Concurrency",
+				Placeholder: "0",
+				Type:        component.InputTypeNumber,
+				Children: []nodx.Node{
+					nodx.Min("0"),
+					nodx.Max("64"),
+					nodx.Value(strconv.Itoa(int(values.UploadConcurrency))),
+				},
+			}),
+		),
+
+		component.InputControl(component.InputControlParams{
+			Name:        "max_running_executions",
+			Label:       "Max running backups",
+			Placeholder: "0",
+			Type:        component.InputTypeNumber,
+			HelpText:    "Maximum number of backups stored in this destination at the same time, use 0 for no limit",
+			Children: []nodx.Node{
+				nodx.Min("0"),
+				nodx.Value(strconv.Itoa(int(values.MaxRunningExecutions))),
+			},
+		}),
+	)
+}
@@ -1,23 +1,48 @@
 package destinations
 
 import (
+	"database/sql"
+
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/validate"
 	"github.com/eduardolat/pgbackweb/internal/view/web/component"
 	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
 	"github.com/labstack/echo/v4"
 	nodx "github.com/nodxdev/nodxgo"
+	alpine "github.com/nodxdev/nodxgo-alpine"
 	htmx "github.com/nodxdev/nodxgo-htmx"
 	lucide "github.com/nodxdev/nodxgo-lucide"
 )
 
 type createDestinationDTO struct {
 	Name       string `form:"name" validate:"required"`
-	BucketName string `form:"bucket_name" validate:"required"`
-	AccessKey  string `form:"access_key" validate:"required"`
-	SecretKey  string `form:"secret_key" validate:"required"`
-	Region     string `form:"region" validate:"required"`
-	Endpoint   string `form:"endpoint" validate:"required"`
+	IsLocal    string `form:"is_local" validate:"required,oneof=true false"`
+	LocalPath  string `form:"local_path" validate:"required_if=IsLocal true"`
+	BucketName string `form:"bucket_name" validate:"required_if=IsLocal false"`
+	AccessKey  string `form:"access_key" validate:"required_if=IsLocal false"`
+	SecretKey  string `form:"secret_key" validate:"required_if=IsLocal false"`
+	Region     string `form:"region" validate:"required_if=IsLocal false"`
+	Endpoint   string `form:"endpoint" validate:"required_if=IsLocal false"`
+
+	UsePathStyle   string `form:"use_path_style" validate:"omitempty,oneof=true false"`
+	StorageClass   string `form:"storage_class"`
+	SSE            string `form:"sse" validate:"omitempty,oneof=AES256 aws:kms"`
+	SSEKMSKeyID    string `form:"sse_kms_key_id"`
+	ObjectLockMode string `form:"object_lock_mode" validate:"omitempty,oneof=GOVERNANCE COMPLIANCE"`
+	ObjectLockDays int32  `form:"object_lock_days" validate:"required_with=ObjectLockMode,min=0"`
+
+	UploadRateLimit   int32 `form:"upload_rate_limit" validate:"min=0"`
+	UploadPartSize    int32 `form:"upload_part_size" validate:"omitempty,min=5,max=1024"`
+	UploadConcurrency int32 `form:"upload_concurrency" validate:"min=0,max=64"`
+
+	LowSpaceThreshold int32 `form:"low_space_threshold" validate:"min=0"`
+
+	MaxRunningExecutions int32 `form:"max_running_executions" validate:"min=0"`
+}
+
+// isLocal returns true if the form data is for a local destination.
+func (dto createDestinationDTO) isLocal() bool {
+	return dto.IsLocal == "true"
 }
 
 func (h *handlers) createDestinationHandler(c echo.Context) error {
@@ -31,16 +56,38 @@ func (h *handlers) createDestinationHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
-	_, err := h.servs.DestinationsService.CreateDestination(
-		ctx, dbgen.DestinationsServiceCreateDestinationParams{
-			Name:       formData.Name,
-			AccessKey:  formData.AccessKey,
-			SecretKey:  formData.SecretKey,
-			Region:     formData.Region,
-			Endpoint:   formData.Endpoint,
-			BucketName: formData.BucketName,
-		},
-	)
+	params := dbgen.DestinationsServiceCreateDestinationParams{
+		Name:           formData.Name,
+		AccessKey:      formData.AccessKey,
+		SecretKey:      formData.SecretKey,
+		Region:         formData.Region,
+		Endpoint:       formData.Endpoint,
+		BucketName:     formData.BucketName,
+		UsePathStyle:   formData.UsePathStyle != "false",
+		StorageClass:   sql.NullString{String: formData.StorageClass, Valid: formData.StorageClass != ""},
+		Sse:            sql.NullString{String: formData.SSE, Valid: formData.SSE != ""},
+		SseKmsKeyID:    sql.NullString{String: formData.SSEKMSKeyID, Valid: formData.SSEKMSKeyID != ""},
+		ObjectLockMode: sql.NullString{String: formData.ObjectLockMode, Valid: formData.ObjectLockMode != ""},
+		ObjectLockDays: sql.NullInt32{Int32: formData.ObjectLockDays, Valid: formData.ObjectLockMode != ""},
+
+		UploadRateLimit:   formData.UploadRateLimit,
+		UploadPartSize:    formData.UploadPartSize,
+		UploadConcurrency: formData.UploadConcurrency,
+	}
+	if formData.isLocal() {
+		params = dbgen.DestinationsServiceCreateDestinationParams{
+			Name:            formData.Name,
+			IsLocal:         true,
+			LocalPath:       sql.NullString{Valid: true, String: formData.LocalPath},
+			UsePathStyle:    true,
+			UploadRateLimit: formData.UploadRateLimit,
+
+			LowSpaceThreshold: formData.LowSpaceThreshold,
+		}
+	}
+	params.MaxRunningExecutions = formData.MaxRunningExecutions
+
+	_, err := h.servs.DestinationsService.CreateDestination(ctx, params)
 	if err != nil {
 		return respondhtmx.ToastError(c, err.Error())
 	}
@@ -66,6 +113,9 @@ func createDestinationButton() nodx.Node {
 			nodx.FormEl(
 				nodx.Id("add-destination-form"),
 				nodx.Class("space-y-2"),
+				alpine.XData(`{
+					is_local: "false",
+				}`),
 
 				component.InputControl(component.InputControlParams{
 					Name:        "name",
@@ -76,47 +126,98 @@ func createDestinationButton() nodx.Node {
 					HelpText:    "A name to easily identify the destination",
 				}),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "bucket_name",
-					Label:       "Bucket name",
-					Placeholder: "my-bucket",
-					Required:    true,
-					Type:        component.InputTypeText,
+				component.SelectControl(component.SelectControlParams{
+					Name:     "is_local",
+					Label:    "Storage type",
+					Required: true,
+					HelpText: "The type of storage where the backups will be stored",
+					Children: []nodx.Node{
+						alpine.XModel("is_local"),
+						nodx.Option(nodx.Value("false"), nodx.Text("S3"), nodx.Selected("")),
+						nodx.Option(nodx.Value("true"), nodx.Text("Local")),
+					},
 				}),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "endpoint",
-					Label:       "Endpoint",
-					Placeholder: "s3-us-west-1.amazonaws.com",
-					Required:    true,
-					Type:        component.InputTypeText,
-				}),
+				alpine.Template(
+					alpine.XIf("is_local == 'true'"),
+					nodx.Div(
+						nodx.Class("space-y-2"),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "region",
-					Label:       "Region",
-					Placeholder: "us-west-1",
-					Required:    true,
-					Type:        component.InputTypeText,
-				}),
+						component.InputControl(component.InputControlParams{
+							Name:        "local_path",
+							Label:       "Local path",
+							Placeholder: "/backups",
+							Required:    true,
+							Type:        component.InputTypeText,
+							HelpText:    localPathHelp,
+						}),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "access_key",
-					Label:       "Access key",
-					Placeholder: "Access key",
-					Required:    true,
-					Type:        component.InputTypeText,
-					HelpText:    "It will be stored securely using PGP encryption.",
-				}),
+						component.InputControl(component.InputControlParams{
+							Name:        "low_space_threshold",
+							Label:       "Low space threshold (GiB)",
+							Placeholder: "0",
+							Type:        component.InputTypeNumber,
+							HelpText:    lowSpaceThresholdHelp,
+							Children: []nodx.Node{
+								nodx.Min("0"),
+								nodx.Value("0"),
+							},
+						}),
+					),
+				),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "secret_key",
-					Label:       "Secret key",
-					Placeholder: "Secret key",
-					Required:    true,
-					Type:        component.InputTypeText,
-					HelpText:    "It will be stored securely using PGP encryption.",
-				}),
+				alpine.Template(
+					alpine.XIf("is_local == 'false'"),
+					nodx.Div(
+						nodx.Class("space-y-2"),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "bucket_name",
+							Label:       "Bucket name",
+							Placeholder: "my-bucket",
+							Required:    true,
+							Type:        component.InputTypeText,
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "endpoint",
+							Label:       "Endpoint",
+							Placeholder: "s3-us-west-1.amazonaws.com",
+							Required:    true,
+							Type:        component.InputTypeText,
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "region",
+							Label:       "Region",
+							Placeholder: "us-west-1",
+							Required:    true,
+							Type:        component.InputTypeText,
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "access_key",
+							Label:       "Access key",
+							Placeholder: "Access key",
+							Required:    true,
+							Type:        component.InputTypeText,
+							HelpText:    "It will be stored securely using PGP encryption.",
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "secret_key",
+							Label:       "Secret key",
+							Placeholder: "Secret key",
+							Required:    true,
+							Type:        component.InputTypeText,
+							HelpText:    "It will be stored securely using PGP encryption.",
+						}),
+
+						s3AdvancedOptions(s3AdvancedValues{UsePathStyle: true}),
+					),
+				),
+
+				uploadLimitsOptions(uploadLimitsValues{}),
 			),
 
 			nodx.Div(
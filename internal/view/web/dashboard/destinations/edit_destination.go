@@ -2,6 +2,7 @@ package destinations
 
 import (
 	"database/sql"
+	"strconv"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/validate"
@@ -30,17 +31,45 @@ func (h *handlers) editDestinationHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
-	_, err = h.servs.DestinationsService.UpdateDestination(
-		ctx, dbgen.DestinationsServiceUpdateDestinationParams{
-			ID:         destinationID,
-			Name:       sql.NullString{String: formData.Name, Valid: true},
-			BucketName: sql.NullString{String: formData.BucketName, Valid: true},
-			Region:     sql.NullString{String: formData.Region, Valid: true},
-			Endpoint:   sql.NullString{String: formData.Endpoint, Valid: true},
-			AccessKey:  sql.NullString{String: formData.AccessKey, Valid: true},
-			SecretKey:  sql.NullString{String: formData.SecretKey, Valid: true},
-		},
-	)
+	params := dbgen.DestinationsServiceUpdateDestinationParams{
+		ID:         destinationID,
+		Name:       sql.NullString{String: formData.Name, Valid: true},
+		BucketName: sql.NullString{String: formData.BucketName, Valid: true},
+		Region:     sql.NullString{String: formData.Region, Valid: true},
+		Endpoint:   sql.NullString{String: formData.Endpoint, Valid: true},
+		AccessKey:  sql.NullString{String: formData.AccessKey, Valid: true},
+		SecretKey:  sql.NullString{String: formData.SecretKey, Valid: true},
+
+		UsePathStyle:   sql.NullBool{Bool: formData.UsePathStyle != "false", Valid: true},
+		StorageClass:   sql.NullString{String: formData.StorageClass, Valid: true},
+		Sse:            sql.NullString{String: formData.SSE, Valid: true},
+		SseKmsKeyID:    sql.NullString{String: formData.SSEKMSKeyID, Valid: true},
+		ObjectLockMode: sql.NullString{String: formData.ObjectLockMode, Valid: true},
+		ObjectLockDays: sql.NullInt32{Int32: formData.ObjectLockDays, Valid: true},
+
+		UploadRateLimit:   sql.NullInt32{Int32: formData.UploadRateLimit, Valid: true},
+		UploadPartSize:    sql.NullInt32{Int32: formData.UploadPartSize, Valid: true},
+		UploadConcurrency: sql.NullInt32{Int32: formData.UploadConcurrency, Valid: true},
+	}
+	if formData.ObjectLockMode == "" {
+		params.ObjectLockDays = sql.NullInt32{Int32: 0, Valid: true}
+	}
+	if formData.isLocal() {
+		params = dbgen.DestinationsServiceUpdateDestinationParams{
+			ID:        destinationID,
+			Name:      sql.NullString{String: formData.Name, Valid: true},
+			LocalPath: sql.NullString{String: formData.LocalPath, Valid: true},
+
+			UploadRateLimit: sql.NullInt32{Int32: formData.UploadRateLimit, Valid: true},
+
+			LowSpaceThreshold: sql.NullInt32{Int32: formData.LowSpaceThreshold, Valid: true},
+		}
+	}
+	params.MaxRunningExecutions = sql.NullInt32{
+		Int32: formData.MaxRunningExecutions, Valid: true,
+	}
+
+	_, err = h.servs.DestinationsService.UpdateDestination(ctx, params)
 	if err != nil {
 		return respondhtmx.ToastError(c, err.Error())
 	}
@@ -86,61 +115,122 @@ func editDestinationButton(
 					},
 				}),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "bucket_name",
-					Label:       "Bucket name",
-					Placeholder: "my-bucket",
-					Required:    true,
-					Type:        component.InputTypeText,
-					Children: []nodx.Node{
-						nodx.Value(destination.BucketName),
-					},
-				}),
+				nodx.Input(
+					nodx.Type("hidden"),
+					nodx.Name("is_local"),
+					nodx.Value(strconv.FormatBool(destination.IsLocal)),
+				),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "endpoint",
-					Label:       "Endpoint",
-					Placeholder: "s3-us-west-1.amazonaws.com",
-					Required:    true,
-					Type:        component.InputTypeText,
-					Children: []nodx.Node{
-						nodx.Value(destination.Endpoint),
-					},
-				}),
+				nodx.If(
+					destination.IsLocal,
+					nodx.Div(
+						nodx.Class("space-y-2"),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "region",
-					Label:       "Region",
-					Placeholder: "us-west-1",
-					Required:    true,
-					Type:        component.InputTypeText,
-					Children: []nodx.Node{
-						nodx.Value(destination.Region),
-					},
-				}),
+						component.InputControl(component.InputControlParams{
+							Name:        "local_path",
+							Label:       "Local path",
+							Placeholder: "/backups",
+							Required:    true,
+							Type:        component.InputTypeText,
+							HelpText:    localPathHelp,
+							Children: []nodx.Node{
+								nodx.Value(destination.LocalPath.String),
+							},
+						}),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "access_key",
-					Label:       "Access key",
-					Placeholder: "Access key",
-					Required:    true,
-					Type:        component.InputTypeText,
-					HelpText:    "It will be stored securely using PGP encryption.",
-					Children: []nodx.Node{
-						nodx.Value(destination.DecryptedAccessKey),
-					},
-				}),
+						component.InputControl(component.InputControlParams{
+							Name:        "low_space_threshold",
+							Label:       "Low space threshold (GiB)",
+							Placeholder: "0",
+							Type:        component.InputTypeNumber,
+							HelpText:    lowSpaceThresholdHelp,
+							Children: []nodx.Node{
+								nodx.Min("0"),
+								nodx.Value(strconv.Itoa(int(destination.LowSpaceThreshold))),
+							},
+						}),
+					),
+				),
 
-				component.InputControl(component.InputControlParams{
-					Name:        "secret_key",
-					Label:       "Secret key",
-					Placeholder: "Secret key",
-					Required:    true,
-					Type:        component.InputTypeText,
-					HelpText:    "It will be stored securely using PGP encryption.",
-					Children: []nodx.Node{
-						nodx.Value(destination.DecryptedSecretKey),
-					},
+				nodx.If(
+					!destination.IsLocal,
+					nodx.Div(
+						nodx.Class("space-y-2"),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "bucket_name",
+							Label:       "Bucket name",
+							Placeholder: "my-bucket",
+							Required:    true,
+							Type:        component.InputTypeText,
+							Children: []nodx.Node{
+								nodx.Value(destination.BucketName),
+							},
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "endpoint",
+							Label:       "Endpoint",
+							Placeholder: "s3-us-west-1.amazonaws.com",
+							Required:    true,
+							Type:        component.InputTypeText,
+							Children: []nodx.Node{
+								nodx.Value(destination.Endpoint),
+							},
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "region",
+							Label:       "Region",
+							Placeholder: "us-west-1",
+							Required:    true,
+							Type:        component.InputTypeText,
+							Children: []nodx.Node{
+								nodx.Value(destination.Region),
+							},
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "access_key",
+							Label:       "Access key",
+							Placeholder: "Access key",
+							Required:    true,
+							Type:        component.InputTypeText,
+							HelpText:    "It will be stored securely using PGP encryption.",
+							Children: []nodx.Node{
+								nodx.Value(destination.DecryptedAccessKey),
+							},
+						}),
+
+						component.InputControl(component.InputControlParams{
+							Name:        "secret_key",
+							Label:       "Secret key",
+							Placeholder: "Secret key",
+							Required:    true,
+							Type:        component.InputTypeText,
+							HelpText:    "It will be stored securely using PGP encryption.",
+							Children: []nodx.Node{
+								nodx.Value(destination.DecryptedSecretKey),
+							},
+						}),
+
+						s3AdvancedOptions(s3AdvancedValues{
+							UsePathStyle:   destination.UsePathStyle,
+							StorageClass:   destination.StorageClass.String,
+							SSE:            destination.Sse.String,
+							SSEKMSKeyID:    destination.SseKmsKeyID.String,
+							ObjectLockMode: destination.ObjectLockMode.String,
+							ObjectLockDays: destination.ObjectLockDays.Int32,
+						}),
+					),
+				),
+
+				uploadLimitsOptions(uploadLimitsValues{
+					UploadRateLimit:   destination.UploadRateLimit,
+					UploadPartSize:    destination.UploadPartSize,
+					UploadConcurrency: destination.UploadConcurrency,
+
+					MaxRunningExecutions: destination.MaxRunningExecutions,
 				}),
 			),
 
@@ -22,10 +22,11 @@ func indexPage(reqCtx reqctx.Ctx) nodx.Node {
 		nodx.Div(
 			nodx.Class("flex justify-between items-start space-x-2"),
 			nodx.Div(
-				component.H1Text("S3 Destinations"),
+				component.H1Text("Destinations"),
 				component.PText(`
-					Here you can manage your S3 destinations. You can skip creating a S3
-					destination if you want to use the local storage for your backups.
+					Here you can manage your destinations. A destination can be a S3
+					compatible storage or a local path in the server, so you can use
+					multiple volumes to store your backups.
 				`),
 			),
 			nodx.Div(
@@ -45,7 +46,8 @@ func indexPage(reqCtx reqctx.Ctx) nodx.Node {
 							nodx.Tr(
 								nodx.Th(nodx.Class("w-1")),
 								nodx.Th(component.SpanText("Name")),
-								nodx.Th(component.SpanText("Bucket name")),
+								nodx.Th(component.SpanText("Type")),
+								nodx.Th(component.SpanText("Bucket name / Path")),
 								nodx.Th(component.SpanText("Endpoint")),
 								nodx.Th(component.SpanText("Region")),
 								nodx.Th(component.SpanText("Access key")),
@@ -54,7 +56,7 @@ func indexPage(reqCtx reqctx.Ctx) nodx.Node {
 							),
 						),
 						nodx.Tbody(
-							component.SkeletonTr(8),
+							component.SkeletonTr(9),
 							htmx.HxGet("/dashboard/destinations/list?page=1"),
 							htmx.HxTrigger("load"),
 						),
@@ -65,7 +67,7 @@ func indexPage(reqCtx reqctx.Ctx) nodx.Node {
 	}
 
 	return layout.Dashboard(reqCtx, layout.DashboardParams{
-		Title: "S3 Destinations",
+		Title: "Destinations",
 		Body:  content,
 	})
 }
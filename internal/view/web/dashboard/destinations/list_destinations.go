@@ -1,6 +1,7 @@
 package destinations
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/eduardolat/pgbackweb/internal/service/destinations"
 	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
 	"github.com/eduardolat/pgbackweb/internal/util/paginateutil"
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
 	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
 	"github.com/eduardolat/pgbackweb/internal/validate"
 	"github.com/eduardolat/pgbackweb/internal/view/web/component"
@@ -76,6 +78,11 @@ func listDestinations(
 					lucide.PlugZap(),
 					component.SpanText("Test connection"),
 				),
+				component.OptionsDropdownA(
+					nodx.Href("/dashboard/destinations/"+destination.ID.String()+"/audit"),
+					lucide.FileSearch(),
+					component.SpanText("Audit files"),
+				),
 				deleteDestinationButton(destination.ID),
 			)),
 			nodx.Td(
@@ -87,39 +94,68 @@ func listDestinations(
 					component.SpanText(destination.Name),
 				),
 			),
-			nodx.Td(
-				nodx.Div(
-					nodx.Class("flex items-center space-x-1"),
-					component.CopyButtonSm(destination.BucketName),
-					component.SpanText(destination.BucketName),
-				),
-			),
-			nodx.Td(
-				nodx.Div(
-					nodx.Class("flex items-center space-x-1"),
-					component.CopyButtonSm(destination.Endpoint),
-					component.SpanText(destination.Endpoint),
-				),
-			),
-			nodx.Td(
-				nodx.Div(
-					nodx.Class("flex items-center space-x-1"),
-					component.CopyButtonSm(destination.Region),
-					component.SpanText(destination.Region),
-				),
-			),
-			nodx.Td(
-				nodx.Div(
-					nodx.Class("flex items-center space-x-1"),
-					component.CopyButtonSm(destination.DecryptedAccessKey),
-					component.SpanText("**********"),
+			nodx.Td(component.PrettyDestinationName(
+				destination.IsLocal, sql.NullString{Valid: true, String: destination.Name},
+			)),
+			nodx.If(
+				destination.IsLocal,
+				nodx.Group(
+					nodx.Td(
+						nodx.Div(
+							nodx.Class("flex items-center space-x-1"),
+							component.CopyButtonSm(destination.LocalPath.String),
+							component.SpanText(destination.LocalPath.String),
+						),
+					),
+					nodx.Td(
+						nodx.Attr("colspan", "4"),
+						nodx.If(
+							destination.FreeSpace.Valid,
+							component.SpanText(
+								"Free space: "+strutil.FormatFileSize(destination.FreeSpace.Int64),
+							),
+						),
+					),
 				),
 			),
-			nodx.Td(
-				nodx.Div(
-					nodx.Class("flex items-center space-x-1"),
-					component.CopyButtonSm(destination.DecryptedSecretKey),
-					component.SpanText("**********"),
+			nodx.If(
+				!destination.IsLocal,
+				nodx.Group(
+					nodx.Td(
+						nodx.Div(
+							nodx.Class("flex items-center space-x-1"),
+							component.CopyButtonSm(destination.BucketName),
+							component.SpanText(destination.BucketName),
+						),
+					),
+					nodx.Td(
+						nodx.Div(
+							nodx.Class("flex items-center space-x-1"),
+							component.CopyButtonSm(destination.Endpoint),
+							component.SpanText(destination.Endpoint),
+						),
+					),
+					nodx.Td(
+						nodx.Div(
+							nodx.Class("flex items-center space-x-1"),
+							component.CopyButtonSm(destination.Region),
+							component.SpanText(destination.Region),
+						),
+					),
+					nodx.Td(
+						nodx.Div(
+							nodx.Class("flex items-center space-x-1"),
+							component.CopyButtonSm(destination.DecryptedAccessKey),
+							component.SpanText("**********"),
+						),
+					),
+					nodx.Td(
+						nodx.Div(
+							nodx.Class("flex items-center space-x-1"),
+							component.CopyButtonSm(destination.DecryptedSecretKey),
+							component.SpanText("**********"),
+						),
+					),
 				),
 			),
 			nodx.Td(component.SpanText(
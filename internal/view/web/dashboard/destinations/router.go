@@ -26,4 +26,7 @@ func MountRouter(
 	parent.DELETE("/:destinationID", h.deleteDestinationHandler)
 	parent.POST("/:destinationID/edit", h.editDestinationHandler)
 	parent.POST("/:destinationID/test", h.testExistingDestinationHandler)
+	parent.GET("/:destinationID/audit", h.auditDestinationPageHandler)
+	parent.GET("/:destinationID/audit/results", h.auditDestinationResultsHandler)
+	parent.POST("/:destinationID/audit/cleanup", h.cleanupDestinationOrphansHandler)
 }
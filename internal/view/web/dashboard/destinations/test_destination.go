@@ -1,6 +1,9 @@
 package destinations
 
 import (
+	"fmt"
+
+	"github.com/eduardolat/pgbackweb/internal/util/strutil"
 	"github.com/eduardolat/pgbackweb/internal/validate"
 	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
 	"github.com/google/uuid"
@@ -16,9 +19,21 @@ func (h *handlers) testDestinationHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
+	if formData.isLocal() {
+		freeBytes, err := h.servs.DestinationsService.TestLocalDestination(
+			formData.LocalPath,
+		)
+		if err != nil {
+			return respondhtmx.ToastError(c, err.Error())
+		}
+
+		return respondhtmx.ToastSuccess(c, localTestSuccessMessage(freeBytes))
+	}
+
 	err := h.servs.DestinationsService.TestDestination(
 		formData.AccessKey, formData.SecretKey, formData.Region, formData.Endpoint,
-		formData.BucketName,
+		formData.BucketName, formData.UsePathStyle != "false",
+		formData.ObjectLockMode != "",
 	)
 	if err != nil {
 		return respondhtmx.ToastError(c, err.Error())
@@ -39,5 +54,23 @@ func (h *handlers) testExistingDestinationHandler(c echo.Context) error {
 		return respondhtmx.ToastError(c, err.Error())
 	}
 
+	dest, err := h.servs.DestinationsService.GetDestination(ctx, destinationID)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	// The free space is stored by the test, the path is not tested again
+	if dest.IsLocal {
+		return respondhtmx.ToastSuccess(
+			c, localTestSuccessMessage(dest.FreeSpace.Int64),
+		)
+	}
+
 	return respondhtmx.ToastSuccess(c, "Connection successful")
 }
+
+func localTestSuccessMessage(freeBytes int64) string {
+	return fmt.Sprintf(
+		"Path is writable, %s of free space", strutil.FormatFileSize(freeBytes),
+	)
+}
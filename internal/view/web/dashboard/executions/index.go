@@ -37,7 +37,11 @@ func (h *handlers) indexPageHandler(c echo.Context) error {
 
 func indexPage(reqCtx reqctx.Ctx, queryData execsQueryData) nodx.Node {
 	content := []nodx.Node{
-		component.H1Text("Executions"),
+		nodx.Div(
+			nodx.Class("flex justify-between items-start"),
+			component.H1Text("Executions"),
+			upcomingExpirationsButton(),
+		),
 		component.CardBox(component.CardBoxParams{
 			Class: "mt-4",
 			Children: []nodx.Node{
@@ -3,6 +3,7 @@ package executions
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
 	"github.com/eduardolat/pgbackweb/internal/service/executions"
@@ -79,12 +80,67 @@ func listExecutions(
 			nodx.Td(component.OptionsDropdown(
 				showExecutionButton(execution),
 				restoreExecutionButton(execution),
+				transferExecutionButton(execution),
+				pinExecutionButton(execution),
 			)),
-			nodx.Td(component.StatusBadge(execution.Status)),
+			nodx.Td(
+				nodx.Class("space-x-1 whitespace-nowrap"),
+				component.StatusBadge(execution.Status),
+				nodx.If(
+					execution.QueuePosition > 0,
+					nodx.SpanEl(
+						nodx.Class("badge badge-outline"),
+						nodx.TitleAttr("Position in the executions queue"),
+						nodx.Text(fmt.Sprintf("#%d", execution.QueuePosition)),
+					),
+				),
+				nodx.If(
+					execution.Status == "queued" && execution.DeferredUntil.Valid &&
+						execution.DeferredUntil.Time.After(time.Now()),
+					nodx.SpanEl(
+						nodx.Class("badge badge-outline badge-warning"),
+						nodx.TitleAttr("Deferred by a blackout window until "+
+							execution.DeferredUntil.Time.Local().Format(
+								timeutil.LayoutYYYYMMDDHHMMSSPretty,
+							),
+						),
+						nodx.Text("Deferred"),
+					),
+				),
+				nodx.If(
+					execution.LifecycleError.Valid,
+					nodx.SpanEl(
+						nodx.Class("badge badge-outline badge-error"),
+						nodx.TitleAttr(fmt.Sprintf(
+							"Lifecycle transfer failed %d times: %s",
+							execution.LifecycleAttempts, execution.LifecycleError.String,
+						)),
+						nodx.Text("Transfer failing"),
+					),
+				),
+				nodx.If(
+					execution.IsUpload,
+					nodx.SpanEl(
+						nodx.Class("badge badge-outline"),
+						nodx.TitleAttr("Uploaded dump, not affected by the retention policies"),
+						nodx.Text("Uploaded"),
+					),
+				),
+				nodx.If(
+					execution.GfsKeeper.Valid,
+					nodx.SpanEl(
+						nodx.Class("badge badge-outline badge-primary"),
+						nodx.TitleAttr("Kept by the GFS retention policy"),
+						nodx.Text("GFS"),
+					),
+				),
+				pinBadge(execution),
+			),
 			nodx.Td(component.SpanText(execution.BackupName)),
 			nodx.Td(component.SpanText(execution.DatabaseName)),
 			nodx.Td(component.PrettyDestinationName(
-				execution.BackupIsLocal, execution.DestinationName,
+				execution.BackupIsLocal || execution.DestinationIsLocal.Bool,
+				execution.DestinationName,
 			)),
 			nodx.Td(component.SpanText(
 				execution.StartedAt.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
@@ -0,0 +1,300 @@
+package executions
+
+import (
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
+	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/reqctx"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) pinExecutionHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+	reqCtx := reqctx.GetCtx(c)
+
+	executionID, err := uuid.Parse(c.Param("executionID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		PinType string `form:"pin_type" validate:"required,oneof=pin legal_hold"`
+		Reason  string `form:"reason" validate:"required"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	err = h.servs.ExecutionsService.PinExecution(
+		ctx, executionID, reqCtx.User.ID, formData.PinType, formData.Reason,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	logger.Info("execution pinned", logger.KV{
+		"execution_id": executionID.String(),
+		"user_id":      reqCtx.User.ID.String(),
+		"user_email":   reqCtx.User.Email,
+		"pin_type":     formData.PinType,
+		"reason":       formData.Reason,
+	})
+
+	return respondhtmx.Refresh(c)
+}
+
+func (h *handlers) unpinExecutionHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+	reqCtx := reqctx.GetCtx(c)
+
+	executionID, err := uuid.Parse(c.Param("executionID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		Reason  string `form:"reason"`
+		Confirm string `form:"confirm"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	execution, err := h.servs.ExecutionsService.GetExecution(ctx, executionID)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	isLegalHold := execution.PinType.String == executions.PinTypeLegalHold
+	if isLegalHold && formData.Confirm != legalHoldReleaseConfirmation {
+		return respondhtmx.ToastError(
+			c, "type "+legalHoldReleaseConfirmation+" to release the legal hold",
+		)
+	}
+
+	err = h.servs.ExecutionsService.UnpinExecution(
+		ctx, executionID, formData.Reason,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	logger.Info("execution unpinned", logger.KV{
+		"execution_id": executionID.String(),
+		"user_id":      reqCtx.User.ID.String(),
+		"user_email":   reqCtx.User.Email,
+		"pin_type":     execution.PinType.String,
+		"reason":       formData.Reason,
+	})
+
+	return respondhtmx.Refresh(c)
+}
+
+// legalHoldReleaseConfirmation is the text the user must type to release a
+// legal hold.
+const legalHoldReleaseConfirmation = "RELEASE"
+
+func pinExecutionButton(
+	execution dbgen.ExecutionsServicePaginateExecutionsRow,
+) nodx.Node {
+	if execution.Status != "success" {
+		return nil
+	}
+
+	if execution.PinType.String == executions.PinTypeLegalHold {
+		return releaseLegalHoldButton(execution)
+	}
+
+	if execution.PinType.Valid {
+		return component.OptionsDropdownButton(
+			htmx.HxDelete("/dashboard/executions/"+execution.ID.String()+"/pin"),
+			htmx.HxConfirm("Are you sure you want to unpin this execution? It will be deleted by the retention rules of its backup again."),
+			htmx.HxDisabledELT("this"),
+			lucide.PinOff(),
+			component.SpanText("Unpin"),
+		)
+	}
+
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Pin execution",
+		Content: []nodx.Node{
+			nodx.FormEl(
+				htmx.HxPost("/dashboard/executions/"+execution.ID.String()+"/pin"),
+				htmx.HxDisabledELT("find button"),
+				nodx.Class("space-y-2 text-base"),
+
+				component.PText(`
+					Pinned executions are never deleted, neither by the retention rules
+					of the backup nor manually, until they are unpinned. Their backup,
+					database and destination can't be deleted either. Releasing a legal
+					hold requires a reason and a typed confirmation.
+				`),
+
+				component.SelectControl(component.SelectControlParams{
+					Name:     "pin_type",
+					Label:    "Type",
+					Required: true,
+					Children: []nodx.Node{
+						nodx.Option(nodx.Value(executions.PinTypePin), nodx.Text("Pin")),
+						nodx.Option(nodx.Value(executions.PinTypeLegalHold), nodx.Text("Legal hold")),
+					},
+				}),
+
+				component.TextareaControl(component.TextareaControlParams{
+					Name:        "reason",
+					Label:       "Reason",
+					Placeholder: "Pre-migration dump of the orders database",
+					Required:    true,
+				}),
+
+				nodx.Div(
+					nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+					component.HxLoadingMd(),
+					nodx.Button(
+						nodx.Class("btn btn-primary"),
+						nodx.Type("submit"),
+						component.SpanText("Pin execution"),
+						lucide.Pin(),
+					),
+				),
+			),
+		},
+	})
+
+	return nodx.Div(
+		mo.HTML,
+		component.OptionsDropdownButton(
+			mo.OpenerAttr,
+			lucide.Pin(),
+			component.SpanText("Pin"),
+		),
+	)
+}
+
+// releaseLegalHoldButton returns the button to release the legal hold of an
+// execution, which requires a reason and a typed confirmation.
+func releaseLegalHoldButton(
+	execution dbgen.ExecutionsServicePaginateExecutionsRow,
+) nodx.Node {
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Release legal hold",
+		Content: []nodx.Node{
+			nodx.FormEl(
+				htmx.HxDelete("/dashboard/executions/"+execution.ID.String()+"/pin"),
+				htmx.HxDisabledELT("find button"),
+				nodx.Class("space-y-2 text-base"),
+
+				component.PText(`
+					Once released, the execution is deleted by the retention rules of
+					its backup again. The release is logged with its reason.
+				`),
+
+				component.TextareaControl(component.TextareaControlParams{
+					Name:        "reason",
+					Label:       "Reason",
+					Placeholder: "The investigation was closed",
+					Required:    true,
+				}),
+
+				component.InputControl(component.InputControlParams{
+					Name:     "confirm",
+					Label:    "Type " + legalHoldReleaseConfirmation + " to confirm",
+					Type:     component.InputTypeText,
+					Required: true,
+					Pattern:  legalHoldReleaseConfirmation,
+				}),
+
+				nodx.Div(
+					nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+					component.HxLoadingMd(),
+					nodx.Button(
+						nodx.Class("btn btn-error"),
+						nodx.Type("submit"),
+						component.SpanText("Release legal hold"),
+						lucide.PinOff(),
+					),
+				),
+			),
+		},
+	})
+
+	return nodx.Div(
+		mo.HTML,
+		component.OptionsDropdownButton(
+			mo.OpenerAttr,
+			lucide.PinOff(),
+			component.SpanText("Release legal hold"),
+		),
+	)
+}
+
+// pinBadge returns the badge shown in the executions list for pinned
+// executions.
+func pinBadge(execution dbgen.ExecutionsServicePaginateExecutionsRow) nodx.Node {
+	if !execution.PinType.Valid {
+		return nil
+	}
+
+	if execution.PinType.String == executions.PinTypeLegalHold {
+		return nodx.SpanEl(
+			nodx.Class("badge badge-outline badge-error"),
+			nodx.TitleAttr(execution.PinReason.String),
+			lucide.Gavel(nodx.Class("size-3 mr-1")),
+			nodx.Text("Legal hold"),
+		)
+	}
+
+	return nodx.SpanEl(
+		nodx.Class("badge badge-outline badge-warning"),
+		nodx.TitleAttr(execution.PinReason.String),
+		lucide.Pin(nodx.Class("size-3 mr-1")),
+		nodx.Text("Pinned"),
+	)
+}
+
+// pinDetailsRows returns the rows shown in the execution details for pinned
+// executions.
+func pinDetailsRows(
+	execution dbgen.ExecutionsServicePaginateExecutionsRow,
+) nodx.Node {
+	if !execution.PinType.Valid {
+		return nil
+	}
+
+	pinnedBy := "Deleted user"
+	if execution.PinnedByEmail.Valid {
+		pinnedBy = execution.PinnedByEmail.String
+	}
+
+	return nodx.Group(
+		nodx.Tr(
+			nodx.Th(component.SpanText("Pinned")),
+			nodx.Td(pinBadge(execution)),
+		),
+		nodx.Tr(
+			nodx.Th(component.SpanText("Pin reason")),
+			nodx.Td(component.SpanText(execution.PinReason.String)),
+		),
+		nodx.Tr(
+			nodx.Th(component.SpanText("Pinned by")),
+			nodx.Td(component.SpanText(
+				pinnedBy+" at "+execution.PinnedAt.Time.Local().Format(
+					timeutil.LayoutYYYYMMDDHHMMSSPretty,
+				),
+			)),
+		),
+	)
+}
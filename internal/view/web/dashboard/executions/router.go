@@ -21,8 +21,14 @@ func MountRouter(
 
 	parent.GET("", h.indexPageHandler)
 	parent.GET("/list", h.listExecutionsHandler)
+	parent.GET("/expirations", h.upcomingExpirationsPageHandler)
+	parent.GET("/expirations/list", h.listUpcomingExpirationsHandler)
 	parent.GET("/:executionID/download", h.downloadExecutionHandler)
 	parent.DELETE("/:executionID", h.deleteExecutionHandler)
+	parent.POST("/:executionID/pin", h.pinExecutionHandler)
+	parent.DELETE("/:executionID/pin", h.unpinExecutionHandler)
 	parent.GET("/:executionID/restore-form", h.restoreExecutionFormHandler)
 	parent.POST("/:executionID/restore", h.restoreExecutionHandler)
+	parent.GET("/:executionID/transfer-form", h.transferExecutionFormHandler)
+	parent.POST("/:executionID/transfer", h.transferExecutionHandler)
 }
@@ -1,11 +1,16 @@
 package executions
 
 import (
+	"database/sql"
+	"fmt"
+	"io"
 	"net/http"
-	"path/filepath"
+	"strconv"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/logger"
 	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/view/reqctx"
 	"github.com/eduardolat/pgbackweb/internal/view/web/component"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -15,24 +20,80 @@ import (
 
 func (h *handlers) downloadExecutionHandler(c echo.Context) error {
 	ctx := c.Request().Context()
+	reqCtx := reqctx.GetCtx(c)
 
 	executionID, err := uuid.Parse(c.Param("executionID"))
 	if err != nil {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	isLocal, link, err := h.servs.ExecutionsService.GetExecutionDownloadLinkOrPath(
-		ctx, executionID,
+	byteRange := c.Request().Header.Get("Range")
+	download, err := h.servs.ExecutionsService.DownloadExecution(
+		ctx, executionID, byteRange,
 	)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
 
-	if isLocal {
-		return c.Attachment(link, filepath.Base(link))
+	mode := "proxy"
+	switch {
+	case download.LocalPath != "":
+		mode = "local"
+	case download.Link != "":
+		mode = "presigned"
 	}
 
-	return c.Redirect(http.StatusFound, link)
+	err = h.servs.ExecutionsService.RecordDownload(
+		ctx, dbgen.ExecutionsServiceCreateDownloadParams{
+			ExecutionID: executionID,
+			UserID:      uuid.NullUUID{Valid: true, UUID: reqCtx.User.ID},
+			UserEmail:   reqCtx.User.Email,
+			Ip:          c.RealIP(),
+			Mode:        mode,
+			ByteRange:   sql.NullString{Valid: byteRange != "", String: byteRange},
+		},
+	)
+	if err != nil {
+		if download.Object != nil {
+			download.Object.Body.Close()
+		}
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	switch mode {
+	case "local":
+		// c.Attachment uses http.ServeContent, which handles Range requests
+		return c.Attachment(download.LocalPath, download.FileName)
+	case "presigned":
+		return c.Redirect(http.StatusFound, download.Link)
+	}
+
+	obj := download.Object
+	defer obj.Body.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/octet-stream")
+	res.Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", download.FileName),
+	)
+	res.Header().Set("Accept-Ranges", "bytes")
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(obj.ContentLength, 10))
+
+	status := http.StatusOK
+	if obj.ContentRange != "" {
+		res.Header().Set("Content-Range", obj.ContentRange)
+		status = http.StatusPartialContent
+	}
+	res.WriteHeader(status)
+
+	if _, err := io.Copy(res, obj.Body); err != nil {
+		logger.Error("error streaming execution download", logger.KV{
+			"execution_id": executionID.String(),
+			"error":        err.Error(),
+		})
+	}
+	return nil
 }
 
 func showExecutionButton(
@@ -54,6 +115,23 @@ func showExecutionButton(
 						nodx.Th(component.SpanText("Status")),
 						nodx.Td(component.StatusBadge(execution.Status)),
 					),
+					nodx.If(
+						execution.GfsKeeper.Valid,
+						nodx.Tr(
+							nodx.Th(component.SpanText("GFS keeper")),
+							nodx.Td(component.SpanText(execution.GfsKeeper.String)),
+						),
+					),
+					nodx.If(
+						execution.QueuePosition > 0,
+						nodx.Tr(
+							nodx.Th(component.SpanText("Queue position")),
+							nodx.Td(component.SpanText(
+								strconv.Itoa(int(execution.QueuePosition)),
+							)),
+						),
+					),
+					pinDetailsRows(execution),
 					nodx.Tr(
 						nodx.Th(component.SpanText("Database")),
 						nodx.Td(component.SpanText(execution.DatabaseName)),
@@ -61,7 +139,8 @@ func showExecutionButton(
 					nodx.Tr(
 						nodx.Th(component.SpanText("Destination")),
 						nodx.Td(component.PrettyDestinationName(
-							execution.BackupIsLocal, execution.DestinationName,
+							execution.BackupIsLocal || execution.DestinationIsLocal.Bool,
+							execution.DestinationName,
 						)),
 					),
 					nodx.If(
@@ -114,12 +193,59 @@ func showExecutionButton(
 							nodx.Td(component.PrettyFileSize(execution.FileSize)),
 						),
 					),
+					nodx.If(
+						execution.LifecycleError.Valid,
+						nodx.Tr(
+							nodx.Th(component.SpanText("Lifecycle transfer")),
+							nodx.Td(
+								nodx.Class("break-all"),
+								component.SpanText(fmt.Sprintf(
+									"Failed %d times, next attempt at %s: %s",
+									execution.LifecycleAttempts,
+									execution.LifecycleRetryAt.Time.Local().Format(
+										timeutil.LayoutYYYYMMDDHHMMSSPretty,
+									),
+									execution.LifecycleError.String,
+								)),
+							),
+						),
+					),
+					nodx.If(
+						execution.DownloadsQty > 0,
+						nodx.Tr(
+							nodx.Th(component.SpanText("Downloads")),
+							nodx.Td(component.SpanText(
+								strconv.Itoa(int(execution.DownloadsQty)),
+							)),
+						),
+					),
+					nodx.If(
+						execution.UploadParts.Int32 > 0,
+						nodx.Tr(
+							nodx.Th(component.SpanText("Upload parts")),
+							nodx.Td(component.SpanText(
+								strconv.Itoa(int(execution.UploadParts.Int32)),
+							)),
+						),
+					),
+					nodx.If(
+						execution.UploadRetries.Int32 > 0,
+						nodx.Tr(
+							nodx.Th(component.SpanText("Upload retries")),
+							nodx.Td(component.SpanText(
+								strconv.Itoa(int(execution.UploadRetries.Int32)),
+							)),
+						),
+					),
 				),
 				nodx.If(
 					execution.Status == "success",
 					nodx.Div(
 						nodx.Class("flex justify-end items-center space-x-2"),
-						deleteExecutionButton(execution.ID),
+						nodx.If(
+							!execution.PinType.Valid,
+							deleteExecutionButton(execution.ID),
+						),
 						nodx.A(
 							nodx.Href("/dashboard/executions/"+execution.ID.String()+"/download"),
 							nodx.Target("_blank"),
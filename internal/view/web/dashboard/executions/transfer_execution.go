@@ -0,0 +1,148 @@
+package executions
+
+import (
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+func (h *handlers) transferExecutionHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	executionID, err := uuid.Parse(c.Param("executionID"))
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	var formData struct {
+		DestinationID uuid.UUID `form:"destination_id" validate:"required,uuid"`
+	}
+	if err := c.Bind(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&formData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	err = h.servs.ExecutionsService.TransferExecution(
+		ctx, executionID, formData.DestinationID,
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return respondhtmx.AlertWithRefresh(c, "Execution transferred and verified")
+}
+
+func (h *handlers) transferExecutionFormHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	executionID, err := uuid.Parse(c.Param("executionID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	destinations, err := h.servs.DestinationsService.GetAllDestinations(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return echoutil.RenderNodx(c, http.StatusOK, transferExecutionForm(
+		executionID, destinations,
+	))
+}
+
+func transferExecutionForm(
+	executionID uuid.UUID,
+	destinations []dbgen.DestinationsServiceGetAllDestinationsRow,
+) nodx.Node {
+	return nodx.FormEl(
+		htmx.HxPost("/dashboard/executions/"+executionID.String()+"/transfer"),
+		htmx.HxConfirm("Are you sure you want to transfer this execution?"),
+		htmx.HxDisabledELT("find button"),
+		nodx.Class("space-y-2 text-base"),
+
+		component.PText(`
+			Move the file of this execution to another destination. The file is
+			verified (size and SHA-256 checksum) before the execution is updated to
+			point to the new destination and the original file is deleted, so it
+			can still be restored.
+		`),
+
+		transferDestinationSelect(destinations),
+
+		nodx.Div(
+			nodx.Class("flex justify-end items-center space-x-2 pt-2"),
+			component.HxLoadingMd(),
+			nodx.Button(
+				nodx.Class("btn btn-primary"),
+				nodx.Type("submit"),
+				component.SpanText("Transfer execution"),
+				lucide.ArrowLeftRight(),
+			),
+		),
+	)
+}
+
+func transferDestinationSelect(
+	destinations []dbgen.DestinationsServiceGetAllDestinationsRow,
+) nodx.Node {
+	return component.SelectControl(component.SelectControlParams{
+		Name:        "destination_id",
+		Label:       "Target destination",
+		Required:    true,
+		Placeholder: "Select a destination",
+		Children: []nodx.Node{
+			nodx.Map(
+				destinations,
+				func(dest dbgen.DestinationsServiceGetAllDestinationsRow) nodx.Node {
+					name := dest.Name
+					if dest.IsLocal {
+						name += " (local)"
+					}
+					return nodx.Option(nodx.Value(dest.ID.String()), nodx.Text(name))
+				},
+			),
+		},
+	})
+}
+
+func transferExecutionButton(
+	execution dbgen.ExecutionsServicePaginateExecutionsRow,
+) nodx.Node {
+	if execution.Status != "success" || !execution.Path.Valid {
+		return nil
+	}
+
+	mo := component.Modal(component.ModalParams{
+		Size:  component.SizeMd,
+		Title: "Transfer execution",
+		Content: []nodx.Node{
+			nodx.Div(
+				htmx.HxGet("/dashboard/executions/"+execution.ID.String()+"/transfer-form"),
+				htmx.HxSwap("outerHTML"),
+				htmx.HxTrigger("intersect once"),
+				nodx.Class("p-10 flex justify-center"),
+				component.HxLoadingMd(),
+			),
+		},
+	})
+
+	return nodx.Div(
+		mo.HTML,
+		component.OptionsDropdownButton(
+			mo.OpenerAttr,
+			lucide.ArrowLeftRight(),
+			component.SpanText("Transfer to destination"),
+		),
+	)
+}
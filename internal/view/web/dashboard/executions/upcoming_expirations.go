@@ -0,0 +1,189 @@
+package executions
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eduardolat/pgbackweb/internal/service/executions"
+	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
+	"github.com/eduardolat/pgbackweb/internal/validate"
+	"github.com/eduardolat/pgbackweb/internal/view/reqctx"
+	"github.com/eduardolat/pgbackweb/internal/view/web/component"
+	"github.com/eduardolat/pgbackweb/internal/view/web/layout"
+	"github.com/eduardolat/pgbackweb/internal/view/web/respondhtmx"
+	"github.com/labstack/echo/v4"
+	nodx "github.com/nodxdev/nodxgo"
+	htmx "github.com/nodxdev/nodxgo-htmx"
+	lucide "github.com/nodxdev/nodxgo-lucide"
+)
+
+type expirationsQueryData struct {
+	Days int `query:"days" validate:"omitempty,min=1,max=365"`
+}
+
+func (q *expirationsQueryData) days() int {
+	if q.Days == 0 {
+		return 30
+	}
+	return q.Days
+}
+
+func (h *handlers) upcomingExpirationsPageHandler(c echo.Context) error {
+	reqCtx := reqctx.GetCtx(c)
+
+	var queryData expirationsQueryData
+	if err := c.Bind(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err := validate.Struct(&queryData); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	return echoutil.RenderNodx(
+		c, http.StatusOK, upcomingExpirationsPage(reqCtx, queryData.days()),
+	)
+}
+
+func (h *handlers) listUpcomingExpirationsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var queryData expirationsQueryData
+	if err := c.Bind(&queryData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+	if err := validate.Struct(&queryData); err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	expirations, err := h.servs.ExecutionsService.GetUpcomingExpirations(
+		ctx, queryData.days(),
+	)
+	if err != nil {
+		return respondhtmx.ToastError(c, err.Error())
+	}
+
+	return echoutil.RenderNodx(
+		c, http.StatusOK, listUpcomingExpirations(expirations),
+	)
+}
+
+func upcomingExpirationsPage(reqCtx reqctx.Ctx, days int) nodx.Node {
+	content := []nodx.Node{
+		nodx.Div(
+			nodx.Class("flex justify-between items-start"),
+			component.H1Text("Upcoming expirations"),
+			nodx.FormEl(
+				nodx.Method("GET"),
+				nodx.Class("flex items-end space-x-2"),
+				component.SelectControl(component.SelectControlParams{
+					Name:  "days",
+					Label: "Next",
+					Children: []nodx.Node{
+						nodx.Map(
+							[]int{7, 30, 90, 365},
+							func(d int) nodx.Node {
+								return nodx.Option(
+									nodx.Value(fmt.Sprintf("%d", d)),
+									nodx.Text(fmt.Sprintf("%d days", d)),
+									nodx.If(d == days, nodx.Selected("")),
+								)
+							},
+						),
+					},
+				}),
+				nodx.Button(
+					nodx.Class("btn btn-primary"),
+					nodx.Type("submit"),
+					component.SpanText("Show"),
+				),
+			),
+		),
+		component.PText(`
+			Executions that will be deleted by the retention rules of their backups,
+			assuming no new executions are created. Pinned executions and the newest
+			successful execution of each backup are never deleted.
+		`),
+		component.CardBox(component.CardBoxParams{
+			Class: "mt-4",
+			Children: []nodx.Node{
+				nodx.Div(
+					nodx.Class("overflow-x-auto"),
+					nodx.Table(
+						nodx.Class("table text-nowrap"),
+						nodx.Thead(
+							nodx.Tr(
+								nodx.Th(component.SpanText("Deleted")),
+								nodx.Th(component.SpanText("Backup")),
+								nodx.Th(component.SpanText("Status")),
+								nodx.Th(component.SpanText("Finished at")),
+								nodx.Th(component.SpanText("File size")),
+							),
+						),
+						nodx.Tbody(
+							component.SkeletonTr(8),
+							htmx.HxGet(fmt.Sprintf(
+								"/dashboard/executions/expirations/list?days=%d", days,
+							)),
+							htmx.HxTrigger("load"),
+						),
+					),
+				),
+			},
+		}),
+	}
+
+	return layout.Dashboard(reqCtx, layout.DashboardParams{
+		Title: "Upcoming expirations",
+		Body:  content,
+	})
+}
+
+func listUpcomingExpirations(
+	expirations []executions.RetentionExpiration,
+) nodx.Node {
+	if len(expirations) < 1 {
+		return component.EmptyResultsTr(component.EmptyResultsParams{
+			Title:    "No upcoming expirations",
+			Subtitle: "No executions will be deleted in this period",
+		})
+	}
+
+	trs := []nodx.Node{}
+	for _, expiration := range expirations {
+		deleted := component.SpanText(
+			expiration.ExpiresAt.Local().Format(timeutil.LayoutDashYYYYMMDD),
+		)
+		if expiration.Immediate {
+			deleted = nodx.SpanEl(
+				nodx.Class("badge badge-error"),
+				nodx.Text("Next retention run"),
+			)
+		}
+
+		trs = append(trs, nodx.Tr(
+			nodx.Td(deleted),
+			nodx.Td(nodx.A(
+				nodx.Class("link"),
+				nodx.Href("/dashboard/executions?backup="+expiration.BackupID.String()),
+				component.SpanText(expiration.BackupName),
+			)),
+			nodx.Td(component.StatusBadge(expiration.Status)),
+			nodx.Td(component.SpanText(
+				expiration.FinishedAt.Local().Format(timeutil.LayoutYYYYMMDDHHMMSSPretty),
+			)),
+			nodx.Td(component.PrettyFileSize(expiration.FileSize)),
+		))
+	}
+
+	return component.RenderableGroup(trs)
+}
+
+func upcomingExpirationsButton() nodx.Node {
+	return nodx.A(
+		nodx.Href("/dashboard/executions/expirations"),
+		nodx.Class("btn btn-ghost"),
+		lucide.CalendarClock(),
+		component.SpanText("Upcoming expirations"),
+	)
+}
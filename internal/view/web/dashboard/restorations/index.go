@@ -37,11 +37,18 @@ func (h *handlers) indexPageHandler(c echo.Context) error {
 func indexPage(reqCtx reqctx.Ctx, queryData resQueryData) nodx.Node {
 	content := []nodx.Node{
 		nodx.Div(
-			component.H1Text("Restorations"),
-			nodx.P(
-				nodx.Text("If PG Back Web has helped you restore your database in an emergency, please"),
-				nodx.Text(" consider supporting the project, "),
-				component.SupportProjectAnchor("learn how here."),
+			nodx.Class("flex justify-between items-start space-x-2"),
+			nodx.Div(
+				component.H1Text("Restorations"),
+				nodx.P(
+					nodx.Text("If PG Back Web has helped you restore your database in an emergency, please"),
+					nodx.Text(" consider supporting the project, "),
+					component.SupportProjectAnchor("learn how here."),
+				),
+			),
+			nodx.Div(
+				nodx.Class("flex-none"),
+				uploadRestorationButton(),
 			),
 		),
 		component.CardBox(component.CardBoxParams{
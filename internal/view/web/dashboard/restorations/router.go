@@ -21,4 +21,6 @@ func MountRouter(
 
 	parent.GET("", h.indexPageHandler)
 	parent.GET("/list", h.listRestorationsHandler)
+	parent.GET("/upload-form", h.uploadRestorationFormHandler)
+	parent.POST("/upload", h.uploadRestorationHandler)
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/eduardolat/pgbackweb/internal/view/middleware"
 	"github.com/eduardolat/pgbackweb/internal/view/web/dashboard/about"
 	"github.com/eduardolat/pgbackweb/internal/view/web/dashboard/backups"
+	"github.com/eduardolat/pgbackweb/internal/view/web/dashboard/blackouts"
 	"github.com/eduardolat/pgbackweb/internal/view/web/dashboard/databases"
 	"github.com/eduardolat/pgbackweb/internal/view/web/dashboard/destinations"
 	"github.com/eduardolat/pgbackweb/internal/view/web/dashboard/executions"
@@ -24,6 +25,7 @@ func MountRouter(
 	databases.MountRouter(parent.Group("/databases"), mids, servs)
 	destinations.MountRouter(parent.Group("/destinations"), mids, servs)
 	backups.MountRouter(parent.Group("/backups"), mids, servs)
+	blackouts.MountRouter(parent.Group("/blackouts"), mids, servs)
 	executions.MountRouter(parent.Group("/executions"), mids, servs)
 	restorations.MountRouter(parent.Group("/restorations"), mids, servs)
 	webhooks.MountRouter(parent.Group("/webhooks"), mids, servs)
@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/eduardolat/pgbackweb/internal/database/dbgen"
+	"github.com/eduardolat/pgbackweb/internal/service/history"
 	"github.com/eduardolat/pgbackweb/internal/util/echoutil"
+	"github.com/eduardolat/pgbackweb/internal/util/timeutil"
 	"github.com/eduardolat/pgbackweb/internal/view/reqctx"
 	"github.com/eduardolat/pgbackweb/internal/view/web/component"
 	"github.com/eduardolat/pgbackweb/internal/view/web/layout"
@@ -38,12 +40,16 @@ func (h *handlers) indexPageHandler(c echo.Context) error {
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
+	historySummary, err := h.servs.HistoryService.GetHistorySummary(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
 
 	return echoutil.RenderNodx(
 		c, http.StatusOK,
 		indexPage(
 			reqCtx, databasesQty, destinationsQty, backupsQty, executionsQty,
-			restorationsQty,
+			restorationsQty, historySummary,
 		),
 	)
 }
@@ -55,6 +61,7 @@ func indexPage(
 	backupsQty dbgen.BackupsServiceGetBackupsQtyRow,
 	executionsQty dbgen.ExecutionsServiceGetExecutionsQtyRow,
 	restorationsQty dbgen.RestorationsServiceGetRestorationsQtyRow,
+	historySummary history.HistorySummary,
 ) nodx.Node {
 	type ChartData struct {
 		Label    string
@@ -151,6 +158,7 @@ func indexPage(
 		redColor    = "#ff5861"
 		yellowColor = "#ffbe00"
 		blueColor   = "#00b6ff"
+		grayColor   = "#a6adbb"
 	)
 
 	content := []nodx.Node{
@@ -180,12 +188,14 @@ func indexPage(
 			}),
 			countCard("Executions", executionsQty.All, ChartData{
 				Label:  "Status",
-				Labels: []string{"Running", "Success", "Failed", "Deleted"},
+				Labels: []string{"Queued", "Running", "Success", "Failed", "Deleted"},
 				Data: []int32{
-					executionsQty.Running, executionsQty.Success, executionsQty.Failed,
-					executionsQty.Deleted,
+					executionsQty.Queued, executionsQty.Running, executionsQty.Success,
+					executionsQty.Failed, executionsQty.Deleted,
+				},
+				BgColors: []string{
+					grayColor, blueColor, greenColor, redColor, yellowColor,
 				},
-				BgColors: []string{blueColor, greenColor, redColor, yellowColor},
 			}),
 			countCard("Restorations", restorationsQty.All, ChartData{
 				Label:  "Status",
@@ -198,6 +208,8 @@ func indexPage(
 			}),
 		),
 
+		historyRetentionCard(historySummary),
+
 		indexHowTo(),
 
 		nodx.Div(
@@ -211,3 +223,46 @@ func indexPage(
 		Body:  content,
 	})
 }
+
+func historyRetentionCard(summary history.HistorySummary) nodx.Node {
+	if !summary.Enabled {
+		return nil
+	}
+
+	lastPurge := "Never"
+	if summary.LastPurgeAt.Valid {
+		lastPurge = summary.LastPurgeAt.Time.Local().Format(
+			timeutil.LayoutYYYYMMDDHHMMSSPretty,
+		)
+	}
+
+	return component.CardBox(component.CardBoxParams{
+		Class: "mt-4",
+		Children: []nodx.Node{
+			component.H2Text("History retention"),
+			component.PText(fmt.Sprintf(
+				"The history of deleted executions, restorations and webhook "+
+					"executions older than %d days is permanently removed. Last purge: %s.",
+				summary.RetentionDays, lastPurge,
+			)),
+			nodx.Div(
+				nodx.Class("mt-2 stats stats-vertical sm:stats-horizontal"),
+				historyStat("Executions purged", summary.Purged.ExecutionsDeleted),
+				historyStat("Restorations purged", summary.Purged.RestorationsDeleted),
+				historyStat(
+					"Webhook executions purged",
+					summary.Purged.WebhookExecutionsDeleted,
+				),
+			),
+		},
+	})
+}
+
+func historyStat(title string, value int64) nodx.Node {
+	return nodx.Div(
+		nodx.Class("stat"),
+		nodx.Div(nodx.Class("stat-title"), nodx.Text(title)),
+		nodx.Div(nodx.Class("stat-value text-2xl"), nodx.Text(fmt.Sprintf("%d", value))),
+		nodx.Div(nodx.Class("stat-desc"), nodx.Text("Last 30 days")),
+	)
+}
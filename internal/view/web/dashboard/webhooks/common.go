@@ -96,6 +96,39 @@ func createAndUpdateWebhookForm(
 		},
 	})
 
+	storageSelect := component.SelectControl(component.SelectControlParams{
+		Name:     "target_ids",
+		Label:    "Storage targets",
+		Required: true,
+		Children: []nodx.Node{
+			alpine.XModel("targetIds"),
+			nodx.Multiple(""),
+			nodx.Option(
+				nodx.Value(webhooks.LocalBackupsTargetID.String()),
+				nodx.Text("Local backups (/backups)"),
+				nodx.If(
+					shouldPrefill && slices.Contains(
+						pickedWebhook.TargetIds, webhooks.LocalBackupsTargetID,
+					),
+					nodx.Selected(""),
+				),
+			),
+			nodx.Map(
+				destinations,
+				func(dest dbgen.DestinationsServiceGetAllDestinationsRow) nodx.Node {
+					return nodx.Option(
+						nodx.Value(dest.ID.String()),
+						nodx.Text(dest.Name),
+						nodx.If(
+							shouldPrefill && slices.Contains(pickedWebhook.TargetIds, dest.ID),
+							nodx.Selected(""),
+						),
+					)
+				},
+			),
+		},
+	})
+
 	backupSelect := component.SelectControl(component.SelectControlParams{
 		Name:     "target_ids",
 		Label:    "Backup targets",
@@ -126,6 +159,7 @@ func createAndUpdateWebhookForm(
 		webhooks.EventTypeDestinationUnhealthy.Value.Key: destinationSelect,
 		webhooks.EventTypeExecutionSuccess.Value.Key:     backupSelect,
 		webhooks.EventTypeExecutionFailed.Value.Key:      backupSelect,
+		webhooks.EventTypeStorageLowSpace.Value.Key:      storageSelect,
 	}
 
 	targetIdsSelect := []nodx.Node{}
@@ -70,6 +70,13 @@ func dashboardAside() nodx.Node {
 				false,
 			),
 
+			dashboardAsideItem(
+				lucide.CalendarOff,
+				"Blackouts",
+				"/dashboard/blackouts",
+				false,
+			),
+
 			dashboardAsideItem(
 				lucide.List,
 				"Executions",